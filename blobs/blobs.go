@@ -0,0 +1,81 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"projet1/models"
+	"projet1/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Acquire ajoute une référence au blob de même hash, ou le crée avec l'objet fourni.
+// L'opération est atomique (INSERT ... ON CONFLICT) : deux envois identiques simultanés
+// partagent le même blob. Un blob manquant ou corrompu est remplacé par le nouvel objet
+func Acquire(tx *gorm.DB, candidate models.Blob) (models.Blob, error) {
+	candidate.RefCount = 1
+	candidate.Status = models.BlobOK
+	err := tx.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "hash"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"ref_count":       gorm.Expr("blobs.ref_count + 1"),
+				"storage_backend": gorm.Expr("CASE WHEN blobs.status = ? THEN blobs.storage_backend ELSE excluded.storage_backend END", models.BlobOK),
+				"storage_key":     gorm.Expr("CASE WHEN blobs.status = ? THEN blobs.storage_key ELSE excluded.storage_key END", models.BlobOK),
+				"status":          models.BlobOK,
+				"updated_at":      gorm.Expr("now()"),
+			}),
+		},
+		clause.Returning{},
+	).Create(&candidate).Error
+	return candidate, err
+}
+
+// Release retire la référence de la version à son blob. Quand plus aucune version ne
+// l'utilise, le blob est supprimé et renvoyé pour effacer son objet du stockage
+func Release(tx *gorm.DB, v models.FileVersion) (*models.Blob, error) {
+	//Ancien fichier sans somme de contrôle : l'objet n'appartient à aucun blob
+	if v.Checksum == "" {
+		inUse, err := objectInUse(tx, v.StorageBackend, v.StorageKey)
+		if err != nil || inUse {
+			return nil, err
+		}
+		return &models.Blob{StorageBackend: v.StorageBackend, StorageKey: v.StorageKey}, nil
+	}
+
+	var blob models.Blob
+	res := tx.Model(&blob).Clauses(clause.Returning{}).Where("hash = ?", v.Checksum).
+		Update("ref_count", gorm.Expr("ref_count - 1"))
+	if res.Error != nil || res.RowsAffected == 0 || blob.RefCount > 0 {
+		return nil, res.Error
+	}
+	if err := tx.Delete(&models.Blob{}, "hash = ?", v.Checksum).Error; err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// Un fichier, une version ou un blob utilise encore cet objet
+func objectInUse(tx *gorm.DB, backend, key string) (bool, error) {
+	var count int64
+	err := tx.Raw(`SELECT (SELECT COUNT(*) FROM file_versions WHERE storage_backend = ? AND storage_key = ?)
+		+ (SELECT COUNT(*) FROM files WHERE storage_backend = ? AND storage_key = ?)
+		+ (SELECT COUNT(*) FROM blobs WHERE storage_backend = ? AND storage_key = ?)`,
+		backend, key, backend, key, backend, key).Scan(&count).Error
+	return count > 0, err
+}
+
+// DeleteObjects efface du stockage les objets qui ne sont plus référencés
+func DeleteObjects(ctx context.Context, blobs []models.Blob) {
+	for _, blob := range blobs {
+		backend, err := storage.Get(blob.StorageBackend)
+		if err == nil {
+			err = backend.Delete(ctx, blob.StorageKey)
+		}
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("objet %s/%s: suppression impossible: %v", blob.StorageBackend, blob.StorageKey, err)
+		}
+	}
+}
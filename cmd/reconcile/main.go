@@ -0,0 +1,44 @@
+// Commande de réconciliation entre la base et le stockage des fichiers.
+//
+//	go run ./cmd/reconcile              rapport seulement
+//	go run ./cmd/reconcile -fix         supprime les objets orphelins et nettoie les lignes sans contenu
+//	go run ./cmd/reconcile -min-age 24h ignore les objets modifiés depuis moins de 24h
+//
+// Le rapport est écrit en JSON sur la sortie standard
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"projet1/database"
+	"projet1/reconcile"
+	"projet1/storage"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "supprimer les objets orphelins et nettoyer les lignes dont l'objet manque")
+	minAge := flag.Duration("min-age", time.Hour, "âge minimal d'un objet orphelin (les plus récents peuvent appartenir à un upload en cours)")
+	flag.Parse()
+
+	godotenv.Load()
+	database.Connect()
+	storage.Connect()
+
+	report, err := reconcile.Reconcile(context.Background(), *fix, *minAge)
+	if err != nil {
+		log.Fatal("Erreur lors de la réconciliation:", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d objet(s) parcouru(s), %d orphelin(s), %d contenu(s) manquant(s)", report.ObjectsScanned, len(report.Orphans), len(report.Missing))
+}
@@ -0,0 +1,19 @@
+package database
+
+import "gorm.io/gorm"
+
+// runOnce exécute une migration de données une seule fois : son nom est enregistré dans
+// schema_migrations dans la même transaction, et les démarrages suivants l'ignorent
+func runOnce(name string, migrate func(tx *gorm.DB) error) error {
+	if err := DB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (name text PRIMARY KEY, applied_at timestamptz NOT NULL DEFAULT now())`).Error; err != nil {
+		return err
+	}
+	return DB.Transaction(func(tx *gorm.DB) error {
+		//Le verrou de la ligne insérée fait attendre une autre instance qui démarre en même temps
+		res := tx.Exec(`INSERT INTO schema_migrations (name) VALUES (?) ON CONFLICT (name) DO NOTHING`, name)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return migrate(tx)
+	})
+}
@@ -0,0 +1,55 @@
+// Recherche plein texte (tsvector + index GIN)
+package database
+
+import "strings"
+
+// Configurations de recherche supportées (langue de la requête -> config PostgreSQL)
+var SearchConfigs = map[string]string{
+	"fr": "french",
+	"en": "english",
+}
+
+// Colonnes tsvector générées pour chaque table et chaque langue
+var searchColumns = map[string]string{
+	"tasks":      "setweight(to_tsvector('{cfg}', coalesce(title, '')), 'A') || setweight(to_tsvector('{cfg}', coalesce(description, '')), 'B')",
+	"users":      "setweight(to_tsvector('{cfg}', coalesce(nom, '') || ' ' || coalesce(prenom, '')), 'A') || setweight(to_tsvector('simple', coalesce(email, '')), 'B')",
+	"files":      "setweight(to_tsvector('{cfg}', coalesce(file_name, '')), 'A')",
+	"file_texts": "setweight(to_tsvector('{cfg}', coalesce(content, '')), 'B')",
+}
+
+// Colonnes indexées en trigrammes pour la tolérance aux fautes de frappe (pas le texte des documents, trop long)
+var trigramColumns = map[string]string{
+	"tasks": "title",
+	"users": "nom",
+	"files": "file_name",
+}
+
+// MigrateSearch ajoute les colonnes search_fr / search_en et leurs index GIN.
+// À appeler après AutoMigrate : les colonnes générées ne sont pas gérées par GORM
+func MigrateSearch() error {
+	statements := []string{"CREATE EXTENSION IF NOT EXISTS pg_trgm"}
+
+	for table, expr := range searchColumns {
+		for lang, cfg := range SearchConfigs {
+			column := "search_" + lang
+			statements = append(statements,
+				"ALTER TABLE "+table+" ADD COLUMN IF NOT EXISTS "+column+
+					" tsvector GENERATED ALWAYS AS ("+strings.ReplaceAll(expr, "{cfg}", cfg)+") STORED",
+				"CREATE INDEX IF NOT EXISTS idx_"+table+"_"+column+" ON "+table+" USING GIN ("+column+")",
+			)
+		}
+		if column, ok := trigramColumns[table]; ok {
+			statements = append(statements,
+				"CREATE INDEX IF NOT EXISTS idx_"+table+"_"+column+"_trgm ON "+table+
+					" USING GIN ("+column+" gin_trgm_ops)",
+			)
+		}
+	}
+
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import "gorm.io/gorm"
+
+// Trigger des compteurs user_task_stats : l'ancienne ligne est retirée, la nouvelle ajoutée
+// (les tâches supprimées, deleted_at renseigné, ne comptent pas)
+const taskStatsTrigger = `CREATE OR REPLACE FUNCTION user_task_stats_apply() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL THEN
+		INSERT INTO user_task_stats (user_id, total, completed, updated_at)
+		VALUES (OLD.user_id, -1, -(OLD.completed::int), now())
+		ON CONFLICT (user_id) DO UPDATE SET total = user_task_stats.total + EXCLUDED.total,
+			completed = user_task_stats.completed + EXCLUDED.completed, updated_at = now();
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+		INSERT INTO user_task_stats (user_id, total, completed, updated_at)
+		VALUES (NEW.user_id, 1, NEW.completed::int, now())
+		ON CONFLICT (user_id) DO UPDATE SET total = user_task_stats.total + EXCLUDED.total,
+			completed = user_task_stats.completed + EXCLUDED.completed, updated_at = now();
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`
+
+// MigrateTaskStats installe les triggers qui tiennent à jour user_task_stats et recalcule
+// les compteurs s'ils sont vides ou faux. À appeler après AutoMigrate (la table est créée par GORM)
+func MigrateTaskStats() error {
+	statements := []string{
+		taskStatsTrigger,
+		"DROP TRIGGER IF EXISTS tasks_stats_insert_delete ON tasks",
+		"CREATE TRIGGER tasks_stats_insert_delete AFTER INSERT OR DELETE ON tasks FOR EACH ROW EXECUTE FUNCTION user_task_stats_apply()",
+		//Les modifications qui ne changent pas les compteurs (titre, description...) ne touchent pas la table
+		"DROP TRIGGER IF EXISTS tasks_stats_update ON tasks",
+		`CREATE TRIGGER tasks_stats_update AFTER UPDATE OF user_id, completed, deleted_at ON tasks FOR EACH ROW
+			WHEN (OLD.user_id IS DISTINCT FROM NEW.user_id OR OLD.completed IS DISTINCT FROM NEW.completed OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at)
+			EXECUTE FUNCTION user_task_stats_apply()`,
+	}
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	consistent, err := taskStatsConsistent()
+	if err != nil || consistent {
+		return err
+	}
+	return RebuildTaskStats()
+}
+
+// Vrai si les compteurs correspondent aux tâches : mêmes totaux, aucun compteur négatif.
+// Une seule requête, donc un seul instantané des deux tables
+func taskStatsConsistent() (bool, error) {
+	var consistent bool
+	err := DB.Raw(`SELECT
+		(SELECT COALESCE(SUM(total), 0) FROM user_task_stats) = (SELECT COUNT(*) FROM tasks WHERE deleted_at IS NULL)
+		AND (SELECT COALESCE(SUM(completed), 0) FROM user_task_stats) = (SELECT COUNT(*) FROM tasks WHERE deleted_at IS NULL AND completed)
+		AND NOT EXISTS (SELECT 1 FROM user_task_stats WHERE total < 0 OR completed < 0 OR completed > total)`).Scan(&consistent).Error
+	return consistent, err
+}
+
+// RebuildTaskStats recalcule tous les compteurs depuis les tâches. Les écritures de tâches
+// attendent la fin du recalcul pour qu'aucune ne soit perdue
+func RebuildTaskStats() error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"LOCK TABLE tasks IN SHARE MODE",
+			"DELETE FROM user_task_stats",
+			`INSERT INTO user_task_stats (user_id, total, completed, updated_at)
+				SELECT user_id, COUNT(*), COUNT(*) FILTER (WHERE completed), now()
+				FROM tasks WHERE deleted_at IS NULL GROUP BY user_id`,
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
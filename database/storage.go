@@ -0,0 +1,33 @@
+package database
+
+import "gorm.io/gorm"
+
+// MigrateFileStorage rattache les fichiers uploadés avant l'introduction des
+// backends de stockage au disque local, avec leur nom de fichier comme clé,
+// et crée la première version des fichiers qui n'en ont pas.
+// La colonne size passe en bigint une seule fois
+func MigrateFileStorage() error {
+	//La taille était un int (2 Go au plus) alors qu'un upload peut être plus gros
+	err := runOnce("file_size_bigint", func(tx *gorm.DB) error {
+		return tx.Exec(`ALTER TABLE files ALTER COLUMN size TYPE bigint`).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := DB.Exec(`UPDATE files SET storage_backend = 'local', storage_key = regexp_replace(path, '^.*/', '')
+		WHERE coalesce(storage_backend, '') = '' AND coalesce(path, '') <> ''`).Error; err != nil {
+		return err
+	}
+
+	//Les anciennes URL publiques /files/... ne sont plus servies
+	if err := DB.Exec(`UPDATE files SET url = '/api/users/get_file/' || id WHERE url LIKE '/files/%'`).Error; err != nil {
+		return err
+	}
+
+	//Les fichiers enregistrés avant le versionnage deviennent leur propre version courante
+	return DB.Exec(`INSERT INTO file_versions (id, file_id, version, file_name, file_type, mime_type, size, checksum, storage_backend, storage_key, user_id, created_at)
+		SELECT gen_random_uuid(), f.id, f.version, f.file_name, f.file_type, f.mime_type, f.size, f.checksum, f.storage_backend, f.storage_key, f.user_id, f.created_at
+		FROM files f
+		WHERE NOT EXISTS (SELECT 1 FROM file_versions v WHERE v.file_id = f.id)`).Error
+}
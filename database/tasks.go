@@ -0,0 +1,15 @@
+package database
+
+import "gorm.io/gorm"
+
+// MigrateTaskDates complète, une seule fois, les dates des tâches enregistrées avant leur horodatage :
+// date de création jamais renseignée (ancienne colonne date sans valeur par défaut)
+// et date de complétion des tâches déjà complétées, approchée par leur dernière modification
+func MigrateTaskDates() error {
+	return runOnce("task_dates", func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE tasks SET created_at = updated_at WHERE created_at < '0002-01-01'`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE tasks SET completed_at = updated_at WHERE completed AND completed_at IS NULL`).Error
+	})
+}
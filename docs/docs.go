@@ -18,30 +18,30 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/tasks/": {
+        "/api/admin/blobs/verify": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Extraire les tâches avec de tout les utilisateurs",
+                "description": "Résultat de la dernière vérification (ou de celle en cours) : contenus manquants ou corrompus et fichiers concernés. Réservé aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
+                    "Admin"
                 ],
-                "summary": "Extraire les tâches",
+                "summary": "Rapport de vérification des fichiers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/response.BlobReport"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -54,43 +54,29 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Création d'une tâche avec les champs JSON fournis",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lance en arrière-plan la relecture de tous les contenus stockés : le SHA-256 est recalculé et comparé, les contenus manquants ou corrompus sont signalés. Les anciens fichiers sans somme de contrôle sont calculés et dédupliqués. Réservé aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
-                ],
-                "summary": "Créer une tâche",
-                "parameters": [
-                    {
-                        "description": "Les données de tache à créer",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Task"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "Vérifier l'intégrité des fichiers stockés",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/response.BlobReport"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "500": {
-                        "description": "Erreur interne",
+                    "409": {
+                        "description": "Vérification déjà en cours",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -98,38 +84,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/filtre_date": {
+        "/api/admin/quarantine": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Filtrer les taches par date avec limite",
+                "description": "Versions de fichiers bloquées par l'analyse antivirus : menace détectée (infected, par défaut), analyse échouée (failed) ou en attente (pending). Réservé aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
+                    "Admin"
                 ],
-                "summary": "Filtrer les taches par date",
+                "summary": "Fichiers en quarantaine",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Date de début",
-                        "name": "start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Date de fin",
-                        "name": "end",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Nombre des jours en arrière",
-                        "name": "jours",
+                        "description": "infected (par défaut), failed ou pending",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -139,12 +113,18 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Task"
+                                "$ref": "#/definitions/models.FileVersion"
                             }
                         }
                     },
                     "400": {
-                        "description": "Requête invalide",
+                        "description": "Statut invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -152,44 +132,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/filtrer": {
-            "get": {
+        "/api/admin/quarantine/{file_id}/rescan": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "filterer les tâches par l'ID de l'utilisateur et la status",
+                "description": "Remet toutes les versions du fichier en quarantaine et relance leur analyse antivirus (après une mise à jour des signatures ou un faux positif). Réservé aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
+                    "Admin"
                 ],
-                "summary": "filterer les tâches",
+                "summary": "Analyser à nouveau un fichier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "L'ID de l'utilisateur",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
                     },
+                    "404": {
+                        "description": "Fichier introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/quotas": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Status du tâche",
-                        "name": "completed",
-                        "in": "query"
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Quota par défaut, quotas par rôle et quotas propres aux utilisateurs. Réservé aux admins",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quota"
+                ],
+                "summary": "Lister les quotas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/response.QuotaList"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -197,40 +209,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/paginated": {
-            "get": {
+        "/api/admin/quotas/roles/{role}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Extraire les tâches avec pagination, en fonction du page et limit",
+                "description": "Quota en octets appliqué aux utilisateurs de ce rôle sans quota propre. Réservé aux admins",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
+                    "Quota"
                 ],
-                "summary": "Extraire les tâches avec pagination",
+                "summary": "Définir le quota d'un rôle",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Les pages",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Rôle",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "La limite des elements",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Quota en octets",
+                        "name": "quota",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.QuotaRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/models.RoleQuota"
                         }
                     },
                     "400": {
@@ -239,35 +258,33 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "404": {
-                        "description": "Tâche introuvable",
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     }
                 }
-            }
-        },
-        "/api/tasks/rate/{user_id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Taux de completion des tâches par l'utilisateur",
+                "description": "Les utilisateurs du rôle reviennent au quota par défaut. Réservé aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
+                    "Quota"
                 ],
-                "summary": "Taux de completion",
+                "summary": "Supprimer le quota d'un rôle",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "L'ID de l'utilisateur",
-                        "name": "user_id",
+                        "description": "Rôle",
+                        "name": "role",
                         "in": "path",
                         "required": true
                     }
@@ -276,11 +293,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.CompletionRate"
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Quota introuvable",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -288,26 +311,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/{id}": {
+        "/api/admin/quotas/users/{user_id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Extraire une tâche avec son ID",
+                "description": "Espace utilisé et quota applicable pour un utilisateur. Réservé aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
+                    "Quota"
                 ],
-                "summary": "Extraire une tâche",
+                "summary": "Stockage d'un utilisateur",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID de la tâche (UUID)",
-                        "name": "id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -316,7 +339,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/response.StorageUsage"
                         }
                     },
                     "400": {
@@ -325,8 +348,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
                     "404": {
-                        "description": "Tâche introuvable",
+                        "description": "Utilisateur introuvable",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -339,7 +368,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mettre à jour les informations d'une tâche avec son ID",
+                "description": "Quota propre à un utilisateur en octets, prioritaire sur celui de son rôle. Réservé aux admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,24 +376,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tâche"
+                    "Quota"
                 ],
-                "summary": "Mettre à jour une tâche",
+                "summary": "Définir le quota d'un utilisateur",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "L'ID du tâche",
-                        "name": "id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Nouvelles données du Tâche",
-                        "name": "task",
+                        "description": "Quota en octets",
+                        "name": "quota",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Task"
+                            "$ref": "#/definitions/response.QuotaRequest"
                         }
                     }
                 ],
@@ -372,7 +401,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/response.StorageUsage"
                         }
                     },
                     "400": {
@@ -381,88 +410,58 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "404": {
-                        "description": "Tâche introuvable",
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
-                    }
-                }
-            }
-        },
-        "/api/users/": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Extraire les utilisateurs avec tous les tâches",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Utilisateur"
-                ],
-                "summary": "Extraire les utilisateurs",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
-                        }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "404": {
+                        "description": "Utilisateur introuvable",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Création de l'utilisateur avec les champs JSON fournis",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "L'utilisateur revient au quota de son rôle ou au quota par défaut. Réservé aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Quota"
                 ],
-                "summary": "Créer un utilisateur",
+                "summary": "Supprimer le quota d'un utilisateur",
                 "parameters": [
                     {
-                        "description": "Les données de l'utilisateur à créer",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/response.StorageUsage"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "500": {
-                        "description": "Erreur interne",
+                    "404": {
+                        "description": "Utilisateur introuvable",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -470,31 +469,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/activity_overview": {
+        "/api/analytics/averages": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupération des utilisateurs avec ces résumés et le taux de completion des taches (Avec Goroutines)",
+                "description": "Nombre de membres et moyennes par utilisateur : tâches, tâches complétées, taux de complétion et série en cours. Les utilisateurs qui refusent les classements ne sont pas comptés, et les groupes de moins de 3 membres ne sont pas renvoyés",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Statistiques"
+                ],
+                "summary": "Moyennes par équipe ou par rôle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "team (défaut) ou role",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre sur les utilisateurs avant regroupement : role, team, total_tasks, completed_tasks, completion_rate (ex. role:eq:user)",
+                        "name": "filter",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Récupération des utilisateurs avec ces résumés",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.GroupAverage"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur introuvable",
+                    "400": {
+                        "description": "Paramètres invalides",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -502,39 +523,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/get_file/{file_id}": {
+        "/api/analytics/leaderboard": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Servir un fichier de la base de données avec son ID",
+                "description": "Classement des utilisateurs par tâches complétées pendant la semaine (by=completed) ou par taux de complétion de la semaine (by=rate) : tâches complétées parmi celles à traiter (créées avant la fin de la semaine et pas complétées avant son début). Les ex-aequo ont le même rang ; les utilisateurs qui refusent les classements n'apparaissent pas",
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Statistiques"
                 ],
-                "summary": "Servir un fichier de la base de données",
+                "summary": "Classement de la semaine",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "File ID",
-                        "name": "file_id",
-                        "in": "path",
-                        "required": true
+                        "description": "completed (défaut) ou rate",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Un jour de la semaine (YYYY-MM-DD), défaut : cette semaine",
+                        "name": "week",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fuseau IANA des semaines (défaut : celui de l'utilisateur connecté, sinon UTC)",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de lignes (défaut 10, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre sur les utilisateurs (ex. team:eq:support,role:eq:user)",
+                        "name": "filter",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File Content",
+                        "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/response.Leaderboard"
                         }
                     },
                     "400": {
-                        "description": "Requête invalide",
+                        "description": "Paramètres invalides",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -548,32 +592,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/paginated_files": {
+        "/api/analytics/streaks": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupération des fichiers avec pagination",
+                "description": "Série en cours et plus longue série de jours consécutifs avec au moins une tâche complétée, dans le fuseau de l'utilisateur. Celle d'un utilisateur qui refuse les classements n'est visible que par lui et les admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Statistiques"
                 ],
-                "summary": "Récupération des fichiers",
+                "summary": "Séries de jours productifs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "le numero du page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "la limite des elements",
-                        "name": "limit",
+                        "description": "Utilisateur (défaut : l'utilisateur connecté)",
+                        "name": "user_id",
                         "in": "query"
                     }
                 ],
@@ -581,12 +619,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/response.UserStreak"
                         }
                     },
                     "400": {
-                        "description": "Requête invalide",
+                        "description": "ID invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -606,32 +649,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/paginated_users": {
+        "/api/analytics/tasks": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Extraire les utilisateurs avec pagination, en fonction du page et limit",
+                "description": "Nombre de tâches créées et complétées par jour, semaine (commençant le lundi) ou mois, sur une période, pour tous les utilisateurs ou regroupées par utilisateur ou par projet. Les intervalles sont découpés dans le fuseau tz, à défaut celui de l'utilisateur connecté, à défaut UTC. Les intervalles sans tâche valent 0. Hors admin, seules les tâches de l'utilisateur connecté et celles partagées avec lui sont comptées ; user_id (autre que soi) et group_by=user sont réservés aux admins",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Statistiques"
                 ],
-                "summary": "Extraire les utilisateurs avec pagination",
+                "summary": "Statistiques des tâches dans le temps",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Les pages",
-                        "name": "page",
+                        "description": "Taille des intervalles : day (défaut), week ou month",
+                        "name": "interval",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "La limite des elements",
-                        "name": "limit",
+                        "description": "Premier jour (YYYY-MM-DD), ramené au début de son intervalle. Défaut : 30 intervalles avant to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dernier jour inclus (YYYY-MM-DD). Défaut : aujourd'hui",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fuseau IANA, ex. Europe/Paris",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "none (défaut), user ou project",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Seulement les tâches de cet utilisateur (@me pour soi)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Seulement les tâches de ce projet",
+                        "name": "project_id",
                         "in": "query"
                     }
                 ],
@@ -639,17 +712,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/response.TaskAnalytics"
                         }
                     },
                     "400": {
-                        "description": "Requête invalide",
+                        "description": "Paramètres invalides ou période trop longue",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur introuvable",
+                    "403": {
+                        "description": "Autre utilisateur ou group_by=user sans être admin",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -657,45 +736,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/upload_file/{user_id}": {
+        "/api/files/archive": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload d'un fichier avec l'ID de li'utilisateur. Les extensions autorisées sont : .pdf, .doc, .docx",
+                "description": "Archive ZIP générée à la volée (sans être conservée en mémoire) avec les fichiers demandés : file_ids, ou tous les fichiers de user_id. Chaque fichier doit être accessible (propriétaire, admin ou partage) et ne pas être en quarantaine ; avec user_id, les fichiers en quarantaine sont ignorés. Les noms en double sont renommés \"nom (2).ext\". La taille totale est limitée par ARCHIVE_MAX_SIZE",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/zip"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Fichier"
                 ],
-                "summary": "Upload fu fichier",
+                "summary": "Télécharger plusieurs fichiers en ZIP",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Fichier pour l'upload",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "file_ids ou user_id",
+                        "name": "archive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.ArchiveRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Fichier importer avec succées",
+                        "description": "Archive ZIP",
                         "schema": {
-                            "$ref": "#/definitions/models.File"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -704,48 +778,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "500": {
-                        "description": "Erreur Interne su serveur",
+                    "403": {
+                        "description": "Accès refusé ou fichier infecté",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
-                    }
-                }
-            }
-        },
-        "/api/users/user_by_email": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Chercher un utilisateur par son Email",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Utilisateur"
-                ],
-                "summary": "Chercher un utilisateur",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Email de l'utilisateur",
-                        "name": "email",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Fichier introuvable",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur introuvable",
+                    "413": {
+                        "description": "Archive trop volumineuse",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "423": {
+                        "description": "Fichier en attente d'analyse antivirus",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -753,36 +805,92 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/user_files/{user_id}": {
+        "/api/files/search": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupérer l'utilisateur et ces fichiers avec le user ID",
+                "description": "Recherche dans le nom des fichiers et dans le texte extrait des PDF et DOCX (version courante). Mêmes paramètres de pagination et de filtre que paginated_files ; en mode offset, les résultats sont classés par pertinence sauf si sort est fourni. Un utilisateur voit ses fichiers et ceux partagés avec lui, un admin tous les fichiers. L'extrait est du HTML échappé",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Recherche"
                 ],
-                "summary": "Récupérer l'utilisateur et ces fichiers",
+                "summary": "Rechercher des fichiers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "L'ID de l'utilisateur",
-                        "name": "user_id",
-                        "in": "path",
+                        "description": "Les termes recherchés",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "La langue de la recherche : fr (par défaut) ou en",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "le numero du page (mode offset)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curseur opaque (mode keyset, vide pour la première page)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "la limite des elements (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. file_type:in:.pdf|.docx)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. -created_at,file_name), mode offset uniquement",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/pagination.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/response.FileSearchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -791,12 +899,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur introuvable",
-                        "schema": {
-                            "$ref": "#/definitions/utils.AppError"
-                        }
-                    },
                     "500": {
                         "description": "Erreur Interne su serveur",
                         "schema": {
@@ -806,31 +908,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/user_overview": {
+        "/api/files/trash": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupération des utilisateurs avec ces résumés et le taux de completion des taches (Sans Goroutines)",
+                "description": "Fichiers supprimés de l'utilisateur connecté, encore restaurables, du plus récent au plus ancien",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Fichier"
                 ],
-                "summary": "Récupération des utilisateurs avec ces résumés",
+                "summary": "Corbeille",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TrashedFile"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur introuvable",
+                    "500": {
+                        "description": "Erreur Interne su serveur",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -838,26 +942,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}": {
-            "get": {
+        "/api/files/trash/{file_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Extraire un utilisatuer avec son ID",
+                "description": "Supprime sans attendre un fichier de la corbeille, ses versions et les contenus qui ne sont plus utilisés",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Fichier"
                 ],
-                "summary": "Extraire un utilisateur",
+                "summary": "Supprimer définitivement un fichier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID de l'itulisateur (UUID)",
-                        "name": "id",
+                        "description": "File ID",
+                        "name": "file_id",
                         "in": "path",
                         "required": true
                     }
@@ -875,47 +979,43 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
                     "404": {
-                        "description": "Utilisateur introuvable",
+                        "description": "Fichier introuvable dans la corbeille",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/files/{file_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mettre à jour les informations d'un utilisateur avec son ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Place le fichier dans la corbeille : il n'est plus listé ni téléchargeable, et ne compte plus dans le quota. Il peut être restauré pendant FILE_TRASH_RETENTION, puis il est supprimé définitivement avec ses versions. Réservé au propriétaire (ou admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Fichier"
                 ],
-                "summary": "Mettre à jour un utilisateur",
+                "summary": "Supprimer un fichier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "L'ID de l'utilisateur",
-                        "name": "id",
+                        "description": "File ID",
+                        "name": "file_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Nouvelles données utilisateur",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
                     }
                 ],
                 "responses": {
@@ -931,65 +1031,87 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
                     "404": {
-                        "description": "Utilisateur introuvable",
+                        "description": "Fichier introuvable",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
+            }
+        },
+        "/api/files/{file_id}/preview": {
+            "get": {
+                "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprimer un utilisateur par son ID",
+                "description": "Vignette PNG d'une image ou première page d'un PDF, pour la version courante du fichier. L'aperçu est généré en arrière-plan après l'upload : 202 tant qu'il n'est pas prêt (réessayer après Retry-After). Réponse cachable, 304 si If-None-Match correspond",
                 "produces": [
-                    "application/json"
+                    "image/png"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Fichier"
                 ],
-                "summary": "Supprimer un utilisateur",
+                "summary": "Aperçu d'un fichier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "L'ID de l'utilisateur",
-                        "name": "id",
+                        "description": "File ID",
+                        "name": "file_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Aperçu PNG",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Aperçu en cours de génération",
                         "schema": {
                             "$ref": "#/definitions/utils.AppSuccessCRUD"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Accès refusé ou fichier infecté",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
                     "404": {
-                        "description": "Utilisateur introuvable",
+                        "description": "Fichier introuvable ou sans aperçu",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "423": {
+                        "description": "Fichier en attente d'analyse antivirus",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/files/{file_id}/public_links": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mise à jour partielle de l'utilisateur par son ID",
+                "description": "Lien en lecture seule vers un fichier, utilisable sans compte jusqu'à son expiration (facultative) ou sa révocation. Avec un mot de passe, il doit être envoyé dans l'en-tête X-Link-Password. Les consultations sont comptées. Réservé au propriétaire (ou admin)",
                 "consumes": [
                     "application/json"
                 ],
@@ -997,32 +1119,84 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Partage"
                 ],
-                "summary": "Mise à jour partielle de l'utilisateur",
+                "summary": "Créer un lien public vers un fichier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "L'ID de l'utilisateur",
-                        "name": "id",
+                        "description": "File ID",
+                        "name": "file_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Les nouvelles données de l'utilisateur",
-                        "name": "updateUser",
+                        "description": "Expiration et mot de passe (facultatifs)",
+                        "name": "link",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/response.UpdateUser"
+                            "$ref": "#/definitions/response.PublicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.PublicLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
                         }
+                    },
+                    "404": {
+                        "description": "Ressource introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/files/{file_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sort un fichier de la corbeille avec toutes ses versions. Le quota du propriétaire est vérifié",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Restaurer un fichier supprimé",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                            "$ref": "#/definitions/models.File"
                         }
                     },
                     "400": {
@@ -1031,8 +1205,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
                     "404": {
-                        "description": "Utilisateur introuvable",
+                        "description": "Fichier introuvable dans la corbeille",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "413": {
+                        "description": "Quota dépassé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
@@ -1040,9 +1226,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/api/files/{file_id}/shares": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Utilisateurs avec qui le fichier est partagé. Réservé au propriétaire (ou admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Partages d'un fichier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Share"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ressource introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Connexion de l'utilisateur avec email et mot de passe",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Donne à un autre utilisateur l'accès en lecture (read, par défaut) ou en modification (edit) à un fichier. Un nouveau partage avec le même utilisateur remplace le précédent. Réservé au propriétaire (ou admin)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1050,18 +1288,84 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Utilisateur"
+                    "Partage"
                 ],
-                "summary": "Connexion de l'utilisateur",
+                "summary": "Partager un fichier",
                 "parameters": [
                     {
-                        "description": "les coordonnées de l'utilisateurs",
-                        "name": "loginRequest",
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Destinataire et droits",
+                        "name": "share",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/response.LoginRequest"
+                            "$ref": "#/definitions/response.ShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Share"
                         }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ressource ou utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/files/{file_id}/shares/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire l'accès d'un utilisateur à un fichier. Le propriétaire (ou un admin) peut retirer n'importe quel partage, le destinataire peut retirer le sien",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Retirer le partage d'un fichier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destinataire du partage",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1077,166 +1381,4881 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
-                    "401": {
-                        "description": "Les coordonnées invalides",
+                    "403": {
+                        "description": "Accès refusé",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     },
                     "404": {
-                        "description": "Utilisateur introuvable",
+                        "description": "Partage introuvable",
                         "schema": {
                             "$ref": "#/definitions/utils.AppError"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.File": {
-            "type": "object",
-            "properties": {
-                "URL": {
-                    "description": "Accessible via HTTP",
-                    "type": "string"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "file_name": {
-                    "type": "string"
-                },
-                "file_size": {
-                    "type": "integer"
-                },
-                "file_type": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "path": {
-                    "description": "Local path au niveau du projet",
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                }
-            }
         },
-        "models.Task": {
-            "type": "object",
-            "properties": {
-                "completed": {
-                    "type": "boolean"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
+        "/api/files/{file_id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Versions conservées d'un fichier, de la plus récente à la plus ancienne ; current indique la version servie par défaut",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Lister les versions d'un fichier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileVersion"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Fichier introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remplace le contenu d'un fichier par une nouvelle version sous le même ID. Les versions précédentes restent consultables (FILE_MAX_VERSIONS au maximum, les plus anciennes sont supprimées). Mêmes vérifications que l'upload : type, taille, quota (du propriétaire). Propriétaire, admin ou partage en modification",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Envoyer une nouvelle version d'un fichier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Nouvelle version",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.File"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Fichier introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "413": {
+                        "description": "Fichier trop volumineux ou quota dépassé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "415": {
+                        "description": "Type non autorisé ou extension incohérente",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/files/{file_id}/versions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Contenu d'une version précise d'un fichier",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Télécharger une version d'un fichier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Afficher dans le navigateur au lieu de télécharger",
+                        "name": "inline",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plage d'octets (ex. bytes=0-1023)",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Fichier ou version introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/files/{file_id}/versions/{version}/promote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée une nouvelle version courante avec le contenu d'une version précédente (l'historique est conservé). Le quota du propriétaire est vérifié",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Restaurer une ancienne version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de version à restaurer",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.File"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Fichier ou version introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "413": {
+                        "description": "Quota dépassé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/storage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Espace utilisé par les fichiers de l'utilisateur connecté et quota applicable (propre, du rôle ou par défaut)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quota"
+                ],
+                "summary": "Mon espace de stockage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.StorageUsage"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire tous les projets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projet"
+                ],
+                "summary": "Extraire les projets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Création d'un projet pour regrouper des tâches",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projet"
+                ],
+                "summary": "Créer un projet",
+                "parameters": [
+                    {
+                        "description": "Les données du projet à créer",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/public_links/{link_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Désactive un lien public avant son expiration (propriétaire de la ressource, créateur du lien ou admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Révoquer un lien public",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du lien",
+                        "name": "link_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lien introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rapports générés en arrière-plan pour l'utilisateur connecté et pas encore expirés, les plus récents d'abord",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rapport"
+                ],
+                "summary": "Mes rapports",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.ReportJob"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Etat d'un rapport généré en arrière-plan (pending, ready ou failed) et son lien de téléchargement quand il est prêt. Demandeur ou admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rapport"
+                ],
+                "summary": "Etat d'un rapport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ReportJob"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rapport introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/jobs/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fichier d'un rapport généré en arrière-plan. Demandeur ou admin",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Rapport"
+                ],
+                "summary": "Télécharger un rapport généré",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rapport",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rapport introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Rapport pas encore prêt ou en échec",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "410": {
+                        "description": "Rapport expiré",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/{kind}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Résumé d'activité des utilisateurs qui acceptent les classements (overview), liste des tâches lisibles par le demandeur (tasks) ou statistiques de complétion globales et par équipe et rôle (stats, groupes d'au moins 3 membres), en CSV, XLSX ou PDF selon le paramètre format ou l'en-tête Accept (CSV par défaut). Au-delà de REPORT_SYNC_MAX_ROWS lignes, ou avec async=true, le rapport est généré en arrière-plan : 202 avec l'état du rapport (Location), à suivre sur /api/reports/jobs/{id} jusqu'à son lien de téléchargement",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "Rapport"
+                ],
+                "summary": "Télécharger un rapport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "overview, tasks ou stats",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv, xlsx ou pdf (prioritaire sur Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre des lignes (champs du résumé d'activité pour overview, sans id ni nom pour stats, des tâches pour tasks)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri des lignes (overview et tasks)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Toujours générer en arrière-plan",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rapport",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Rapport en cours de génération",
+                        "schema": {
+                            "$ref": "#/definitions/response.ReportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Filtre ou tri invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Type de rapport inconnu",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "406": {
+                        "description": "Format non supporté",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recherche dans les tâches, les utilisateurs et les fichiers, classée par pertinence. Les mots sont recherchés par préfixe et les fautes de frappe sont tolérées (trigrammes). Hors admin, seules les tâches et fichiers de l'utilisateur ou partagés avec lui sont renvoyés. L'extrait est du HTML échappé où seuls les termes trouvés sont entre \u003cmark\u003e\u003c/mark\u003e",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recherche"
+                ],
+                "summary": "Recherche plein texte",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Les termes recherchés",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "La langue de la recherche : fr (par défaut) ou en",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Les types séparés par des virgules : task,user,file (tous par défaut)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "le numero du page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "la limite des elements (max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/shares/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Partages et liens publics (y compris expirés ou révoqués, avec le nombre de consultations) des fichiers et tâches de l'utilisateur connecté, du plus récent au plus ancien",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Ce que j'ai partagé",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.SharedByMe"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/shares/received": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fichiers et tâches que d'autres utilisateurs ont partagés avec l'utilisateur connecté",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Partagés avec moi",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.SharedResource"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire les tâches possédées ou partagées (toutes pour un admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Extraire les tâches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. completed:eq:true,created_at:gt:2025-01-01)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. -created_at,title)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Création d'une tâche avec les champs JSON fournis",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Créer une tâche",
+                "parameters": [
+                    {
+                        "description": "Les données de tache à créer",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Appliquer complete, reopen, delete, retag, reassign ou move à une liste d'IDs ou aux tâches correspondant à un filtre, dans une seule transaction. Avec dry_run, rien n'est enregistré et la réponse décrit ce qui changerait. Chaque tâche exige le droit de modification (propriétaire pour delete et reassign) ; les autres sont renvoyées en forbidden. Un filtre vide est réservé aux admins",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Opération groupée sur les tâches",
+                "parameters": [
+                    {
+                        "description": "L'action et les tâches ciblées",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.BulkTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.BulkTaskResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Filtre vide réservé aux admins",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur ou projet introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "413": {
+                        "description": "Trop de tâches ciblées",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "422": {
+                        "description": "Echec de validation",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/filtre_date": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Filtrer les taches par date avec limite",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Filtrer les taches par date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date de début",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date de fin",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre des jours en arrière",
+                        "name": "jours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. completed:eq:true)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. -created_at,title)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/filtrer": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "filterer les tâches par l'ID de l'utilisateur et la status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "filterer les tâches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID de l'utilisateur",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status du tâche",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. completed:eq:true,created_at:gt:2025-01-01)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. -created_at,title)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/paginated": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire les tâches possédées ou partagées (toutes pour un admin) avec pagination, en fonction du page et limit",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Extraire les tâches avec pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Les pages (mode offset)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curseur opaque (mode keyset, vide pour la première page)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "La limite des elements (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. completed:eq:true,created_at:gt:2025-01-01)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. -created_at,title), mode offset uniquement",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Envelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/rate/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Taux de completion des tâches par l'utilisateur",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Taux de completion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID de l'utilisateur",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.CompletionRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire une tâche avec son ID (propriétaire, admin ou tâche partagée)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Extraire une tâche",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la tâche (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Tâche introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mettre à jour les informations d'une tâche avec son ID (propriétaire, admin ou partage en modification ; seul le propriétaire peut changer user_id)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Mettre à jour une tâche",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID du tâche",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelles données du Tâche",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Tâche introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprimer une tâche par son ID (propriétaire ou admin) ; ses partages et liens publics sont supprimés",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tâche"
+                ],
+                "summary": "Supprimer une tâche",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID du tâche",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Tâche introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}/public_links": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lien en lecture seule vers une tâche, utilisable sans compte jusqu'à son expiration (facultative) ou sa révocation. Avec un mot de passe, il doit être envoyé dans l'en-tête X-Link-Password. Les consultations sont comptées. Réservé au propriétaire (ou admin)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Créer un lien public vers une tâche",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la tâche",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expiration et mot de passe (facultatifs)",
+                        "name": "link",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.PublicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.PublicLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ressource introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}/shares": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Utilisateurs avec qui la tâche est partagée. Réservé au propriétaire (ou admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Partages d'une tâche",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la tâche",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Share"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ressource introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Donne à un autre utilisateur l'accès en lecture (read, par défaut) ou en modification (edit) à une tâche. Un nouveau partage avec le même utilisateur remplace le précédent. Réservé au propriétaire (ou admin)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Partager une tâche",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la tâche",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Destinataire et droits",
+                        "name": "share",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.ShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Share"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ressource ou utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}/shares/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire l'accès d'un utilisateur à une tâche. Le propriétaire (ou un admin) peut retirer n'importe quel partage, le destinataire peut retirer le sien",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Retirer le partage d'une tâche",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la tâche",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destinataire du partage",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Partage introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploads/": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Création d'un upload tus. Upload-Length est obligatoire, Upload-Metadata doit contenir filename (base64). Le nom et la taille sont vérifiés avec la politique d'upload ; le contenu l'est à la fin de l'upload",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Créer un upload en plusieurs morceaux",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Taille totale du fichier",
+                        "name": "Upload-Length",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filename \u003cbase64\u003e, filetype \u003cbase64\u003e",
+                        "name": "Upload-Metadata",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL de l'upload"
+                            },
+                            "Upload-Expires": {
+                                "type": "string",
+                                "description": "Date d'expiration"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "412": {
+                        "description": "Version tus non supportée",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "413": {
+                        "description": "Fichier trop volumineux ou quota dépassé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "415": {
+                        "description": "Type non autorisé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Découverte du protocole tus : version, extensions supportées (creation, termination, expiration) et taille maximale",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Capacités du serveur tus",
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "Tus-Extension": {
+                                "type": "string",
+                                "description": "creation,termination,expiration"
+                            },
+                            "Tus-Max-Size": {
+                                "type": "int",
+                                "description": "Taille maximale d'un fichier"
+                            },
+                            "Tus-Version": {
+                                "type": "string",
+                                "description": "1.0.0"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploads/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime un upload et les morceaux déjà reçus (extension tus termination)",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Annuler un upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'upload",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Upload introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "423": {
+                        "description": "Envoi en cours",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renvoie le nombre d'octets déjà reçus (Upload-Offset) pour reprendre l'envoi. Upload-File-Id est présent quand l'upload est terminé",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Position d'un upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'upload",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "headers": {
+                            "Upload-File-Id": {
+                                "type": "string",
+                                "description": "ID du fichier créé"
+                            },
+                            "Upload-Length": {
+                                "type": "int",
+                                "description": "Taille totale"
+                            },
+                            "Upload-Offset": {
+                                "type": "int",
+                                "description": "Octets reçus"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Upload introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "410": {
+                        "description": "Upload expiré",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute le corps de la requête à partir de Upload-Offset, qui doit être égal aux octets déjà reçus. Quand tout est reçu, le contenu est vérifié, stocké et un fichier est créé (Upload-File-Id)",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Envoyer un morceau",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'upload",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position du morceau",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "Upload-File-Id": {
+                                "type": "string",
+                                "description": "ID du fichier créé"
+                            },
+                            "Upload-Offset": {
+                                "type": "int",
+                                "description": "Octets reçus"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Upload introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Upload-Offset incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "410": {
+                        "description": "Upload expiré",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "413": {
+                        "description": "Morceau au-delà de Upload-Length ou quota dépassé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type invalide ou contenu refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "423": {
+                        "description": "Envoi déjà en cours",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire les utilisateurs avec tous les tâches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Extraire les utilisateurs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. role:in:admin|user,nom:contains:dup)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. nom,-created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Création de l'utilisateur avec les champs JSON fournis",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Créer un utilisateur",
+                "parameters": [
+                    {
+                        "description": "Les données de l'utilisateur à créer",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/activity_overview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Résumé paginé des utilisateurs avec le nombre de tâches et le taux de complétion, calculé en une requête agrégée. Les utilisateurs qui refusent les classements (leaderboard_opt_out) n'apparaissent pas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Récupération des utilisateurs avec ces résumés",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page (mode offset uniquement)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre d'utilisateurs par page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. role:eq:admin,total_tasks:gt:10)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri : completion_rate, total_tasks, completed_tasks, nom (ex. -completion_rate)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Envelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/activity_overview_anonyme": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Résumé paginé des utilisateurs avec le nombre de tâches et le taux de complétion. Les utilisateurs qui refusent les classements (leaderboard_opt_out) apparaissent sans ID ni nom, sauf pour eux-mêmes ; on ne peut pas filtrer ni trier sur id ou nom",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Récupération des utilisateurs avec ces résumés (anonymisé)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page (mode offset uniquement)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre d'utilisateurs par page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. role:eq:admin,total_tasks:gt:10)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri : completion_rate, total_tasks, completed_tasks (ex. -completion_rate)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Envelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/file_link/{file_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Générer un lien signé (HMAC) et temporaire vers un fichier. Le lien peut être utilisé sans token jusqu'à son expiration ou sa révocation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Générer un lien de téléchargement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Durée de validité en secondes (15 minutes par défaut, 24h maximum)",
+                        "name": "ttl",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.DownloadLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Fichier introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/file_link/{link_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Révoquer un lien de téléchargement avant son expiration (créateur du lien, propriétaire du fichier ou admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Révoquer un lien de téléchargement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du lien",
+                        "name": "link_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lien introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/get_file/{file_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Servir un fichier de la base de données avec son ID, sous son nom d'origine. Supporte les requêtes conditionnelles (If-None-Match, If-Modified-Since) et partielles (Range, If-Range) pour reprendre un téléchargement",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Servir un fichier de la base de données",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Afficher dans le navigateur (Content-Disposition inline) au lieu de télécharger",
+                        "name": "inline",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plage d'octets (ex. bytes=0-1023)",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag déjà connu",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé ou fichier infecté",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "416": {
+                        "description": "Plage invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "423": {
+                        "description": "Fichier en attente d'analyse antivirus",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/global_stat": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Global stat (requêtes en parallèle, arrêtées si le client se déconnecte), avec le nombre de fichiers et l'espace de stockage utilisé. Les tâches sont lues dans les compteurs par utilisateur et le résultat est gardé en cache (STATS_CACHE_TTL) : computed_at, cached et les en-têtes Age / X-Stats-Computed-At indiquent sa fraîcheur",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Global stat",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.UserStat"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/global_stat_overview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Global stat (requêtes en parallèle : la première erreur arrête les autres), sans les fichiers. Résultat gardé en cache comme /api/users/global_stat",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Global stat avec channel",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.UserStat"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/paginated_files": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupération des fichiers possédés ou partagés (tous pour un admin) avec pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Récupération des fichiers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "le numero du page (mode offset)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curseur opaque (mode keyset, vide pour la première page)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "la limite des elements (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. file_type:in:.pdf|.docx,file_size:gt:1000)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. -created_at,file_name), mode offset uniquement",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Envelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/paginated_users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire les utilisateurs avec pagination, en fonction du page et limit",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Extraire les utilisateurs avec pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Les pages (mode offset)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curseur opaque (mode keyset, vide pour la première page)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "La limite des elements (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. role:in:admin|user,nom:contains:dup)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri (ex. nom,-created_at), mode offset uniquement",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Envelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/upload_file/{user_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload d'un fichier avec l'ID de li'utilisateur (l'utilisateur lui-même ou un admin). Le type est détecté à partir du contenu et doit faire partie des types autorisés (par défaut .pdf, .doc, .docx, configurable avec UPLOAD_ALLOWED_TYPES), avec une taille maximale par type. L'extension doit correspondre au contenu et le nom est assaini",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Upload fu fichier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Fichier pour l'upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fichier importer avec succées",
+                        "schema": {
+                            "$ref": "#/definitions/models.File"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide, fichier vide (EMPTY_FILE) ou nom invalide (INVALID_FILE_NAME)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "413": {
+                        "description": "Fichier trop volumineux pour son type (FILE_TOO_LARGE) ou quota dépassé (QUOTA_EXCEEDED)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "415": {
+                        "description": "Type non autorisé (FILE_TYPE_NOT_ALLOWED) ou extension incohérente (FILE_TYPE_MISMATCH)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/user_by_email": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Chercher un utilisateur par son Email",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Chercher un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email de l'utilisateur",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/user_files/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupérer l'utilisateur et ces fichiers avec le user ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Récupérer l'utilisateur et ces fichiers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID de l'utilisateur",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/user_overview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Résumé paginé des utilisateurs avec le nombre de tâches et le taux de complétion (même résultat que /api/users/activity_overview, sans les utilisateurs qui refusent les classements)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Récupération des utilisateurs avec ces résumés",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page (mode offset uniquement)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre d'utilisateurs par page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtre (ex. role:eq:admin,total_tasks:gt:10)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri : completion_rate, total_tasks, completed_tasks, nom (ex. -completion_rate)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Envelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur Interne su serveur",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire un utilisatuer avec son ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Extraire un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'itulisateur (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mettre à jour les informations d'un utilisateur avec son ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Mettre à jour un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelles données utilisateur",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprimer un utilisateur par son ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Supprimer un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mise à jour partielle de l'utilisateur par son ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Mise à jour partielle de l'utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "L'ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Les nouvelles données de l'utilisateur",
+                        "name": "updateUser",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.UpdateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/views/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire les listes intelligentes, les vues de l'utilisateur et celles partagées avec lui",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vue"
+                ],
+                "summary": "Extraire les vues",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enregistrer une requête de tâches nommée (filtre, tri, regroupement), éventuellement partagée avec des coéquipiers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vue"
+                ],
+                "summary": "Créer une vue",
+                "parameters": [
+                    {
+                        "description": "La définition de la vue",
+                        "name": "view",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.SavedViewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/views/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire une vue par son ID ou la clé d'une liste intelligente (today, overdue, recently_completed)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vue"
+                ],
+                "summary": "Extraire une vue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la vue ou clé de la liste intelligente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Vue introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mettre à jour une vue et la liste des coéquipiers avec qui elle est partagée (propriétaire uniquement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vue"
+                ],
+                "summary": "Mettre à jour une vue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la vue",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "La nouvelle définition de la vue",
+                        "name": "view",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.SavedViewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Vue introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprimer une vue par son ID (propriétaire uniquement)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vue"
+                ],
+                "summary": "Supprimer une vue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la vue",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Vue introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/views/{id}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extraire les tâches correspondant à une vue ou une liste intelligente, avec pagination. Si la vue a un regroupement, les éléments de la page sont des groupes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vue"
+                ],
+                "summary": "Exécuter une vue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la vue ou clé de la liste intelligente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Les pages (mode offset)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curseur opaque (vues sans tri ni regroupement)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "La limite des elements (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclure le nombre total",
+                        "name": "total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Envelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Vue introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/download/{link_id}": {
+            "get": {
+                "description": "Télécharger un fichier avec un lien généré par /api/users/file_link. La signature, l'expiration, la révocation et les droits du créateur du lien sont vérifiés",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Fichier"
+                ],
+                "summary": "Télécharger un fichier avec un lien signé",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du lien",
+                        "name": "link_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiration (timestamp Unix)",
+                        "name": "exp",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature",
+                        "name": "sig",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Afficher dans le navigateur au lieu de télécharger",
+                        "name": "inline",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plage d'octets (ex. bytes=0-1023)",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Signature invalide ou accès refusé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "410": {
+                        "description": "Lien expiré ou révoqué",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Connexion de l'utilisateur avec email et mot de passe",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Utilisateur"
+                ],
+                "summary": "Connexion de l'utilisateur",
+                "parameters": [
+                    {
+                        "description": "les coordonnées de l'utilisateurs",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/response.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppSuccessCRUD"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "Les coordonnées invalides",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/{token}": {
+            "get": {
+                "description": "Fichier (informations et lien de téléchargement) ou tâche partagé par un lien public, sans token. Chaque consultation d'une tâche est comptée ; pour un fichier, seuls les téléchargements le sont. Après plusieurs mots de passe incorrects, le lien est bloqué pour une durée croissante",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Consulter un lien public",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jeton du lien",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mot de passe du lien",
+                        "name": "X-Link-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.PublicResource"
+                        }
+                    },
+                    "401": {
+                        "description": "Mot de passe manquant ou incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lien ou ressource introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "410": {
+                        "description": "Lien expiré ou révoqué",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "Trop de mots de passe incorrects, réessayer après Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/{token}/download": {
+            "get": {
+                "description": "Contenu du fichier partagé par un lien public, sans token. Chaque téléchargement est compté comme une consultation, sauf les requêtes partielles (Range)",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Partage"
+                ],
+                "summary": "Télécharger un fichier avec un lien public",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jeton du lien",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mot de passe du lien",
+                        "name": "X-Link-Password",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Afficher dans le navigateur au lieu de télécharger",
+                        "name": "inline",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plage d'octets (ex. bytes=0-1023)",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Mot de passe manquant ou incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Fichier infecté",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lien ou fichier introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "410": {
+                        "description": "Lien expiré ou révoqué",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "423": {
+                        "description": "Fichier en attente d'analyse antivirus",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "Trop de mots de passe incorrects, réessayer après Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/utils.AppError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.File": {
+            "type": "object",
+            "properties": {
+                "URL": {
+                    "description": "Accessible via HTTP",
+                    "type": "string"
+                },
+                "checksum": {
+                    "description": "SHA-256 du contenu (Blob)",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "description": "Type détecté à partir du contenu",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Local path au niveau du projet (anciens fichiers)",
+                    "type": "string"
+                },
+                "preview_error": {
+                    "type": "string"
+                },
+                "preview_status": {
+                    "description": "pending, ready, failed ou unsupported",
+                    "type": "string"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "scan_signature": {
+                    "description": "Menace détectée",
+                    "type": "string"
+                },
+                "scan_status": {
+                    "description": "Analyse antivirus de la version courante",
+                    "type": "string"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "text_error": {
+                    "type": "string"
+                },
+                "text_status": {
+                    "description": "Extraction du texte : pending, ready, failed ou unsupported",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Numéro de la version courante",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FileVersion": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "promoted_from": {
+                    "description": "Version restaurée par cette version",
+                    "type": "integer"
+                },
+                "scan_signature": {
+                    "type": "string"
+                },
+                "scan_status": {
+                    "type": "string"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Auteur de la version",
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Project": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "description": "Foreign key (taches)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Task"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Propriétaire du projet",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RoleQuota": {
+            "type": "object",
+            "properties": {
+                "quota_bytes": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Share": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "Propriétaire de la ressource",
+                    "type": "string"
+                },
+                "permission": {
+                    "description": "read ou edit",
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "description": "file ou task",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Destinataire du partage",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Task": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "completed_at": {
+                    "description": "Date de complétion (null si la tâche est ouverte)",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "description": "Echéance (optionnelle)",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "Projet (optionnel)",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Les étiquettes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "date_naissance": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "files": {
+                    "description": "Foreign key (fichiers)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.File"
+                    }
+                },
+                "genre": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "leaderboard_opt_out": {
+                    "description": "L'utilisateur n'apparaît pas dans les classements et est anonymisé dans le résumé anonyme",
+                    "type": "boolean"
+                },
+                "nom": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "prenom": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "description": "Foreign key (taches)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Task"
+                    }
+                },
+                "team": {
+                    "description": "Equipe, pour les moyennes par équipe",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Fuseau IANA (ex. Europe/Paris) des statistiques, UTC si vide",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserQuota": {
+            "type": "object",
+            "properties": {
+                "quota_bytes": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "pagination.Envelope": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {},
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.AnalyticsSeries": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "key": {
+                    "description": "ID de l'utilisateur ou du projet, \"all\" sans regroupement, \"none\" pour les tâches sans projet",
+                    "type": "string"
+                },
+                "label": {
+                    "description": "Nom affichable",
+                    "type": "string"
+                },
+                "total_completed": {
+                    "type": "integer"
+                },
+                "total_created": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.ArchiveRequest": {
+            "type": "object",
+            "properties": {
+                "file_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BlobProblem": {
+            "type": "object",
+            "properties": {
+                "file_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "hash": {
+                    "description": "Vide pour un ancien fichier jamais vérifié",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "missing ou corrupt",
+                    "type": "string"
+                },
+                "storage_backend": {
+                    "type": "string"
+                },
+                "storage_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BlobReport": {
+            "type": "object",
+            "properties": {
+                "backfilled": {
+                    "description": "Anciens fichiers dont le SHA-256 a été calculé",
+                    "type": "integer"
+                },
+                "checked": {
+                    "description": "Blobs relus et comparés à leur SHA-256",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BlobProblem"
+                    }
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BulkItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "updated, unchanged, deleted, not_found, forbidden, failed",
+                    "type": "string"
+                }
+            }
+        },
+        "response.BulkTaskFilter": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "created_after": {
+                    "type": "string"
+                },
+                "created_before": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BulkTaskRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "complete",
+                        "reopen",
+                        "delete",
+                        "retag",
+                        "reassign",
+                        "move"
+                    ]
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/response.BulkTaskFilter"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "project_id": {
+                    "description": "Pour l'action move (null pour retirer du projet)",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Pour l'action retag",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "Pour l'action reassign",
+                    "type": "string"
+                }
+            }
+        },
+        "response.BulkTaskResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "changed": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BulkItemResult"
+                    }
+                },
+                "matched": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.CompletionRate": {
+            "type": "object",
+            "properties": {
+                "completion_rate": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.DownloadLink": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Lien à utiliser sans token jusqu'à expires_at",
+                    "type": "string"
+                }
+            }
+        },
+        "response.FileSearchResult": {
+            "type": "object",
+            "properties": {
+                "URL": {
+                    "description": "Accessible via HTTP",
+                    "type": "string"
+                },
+                "checksum": {
+                    "description": "SHA-256 du contenu (Blob)",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "description": "Type détecté à partir du contenu",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Local path au niveau du projet (anciens fichiers)",
+                    "type": "string"
+                },
+                "preview_error": {
+                    "type": "string"
+                },
+                "preview_status": {
+                    "description": "pending, ready, failed ou unsupported",
+                    "type": "string"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "scan_signature": {
+                    "description": "Menace détectée",
+                    "type": "string"
+                },
+                "scan_status": {
+                    "description": "Analyse antivirus de la version courante",
+                    "type": "string"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "description": "Extrait du texte (ou du nom) en HTML échappé, avec les termes trouvés entre \u003cmark\u003e\u003c/mark\u003e",
+                    "type": "string"
+                },
+                "text_error": {
+                    "type": "string"
+                },
+                "text_status": {
+                    "description": "Extraction du texte : pending, ready, failed ou unsupported",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Numéro de la version courante",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.GroupAverage": {
+            "type": "object",
+            "properties": {
+                "avg_completed_tasks": {
+                    "type": "number"
+                },
+                "avg_completion_rate": {
+                    "type": "number"
+                },
+                "avg_current_streak": {
+                    "type": "number"
+                },
+                "avg_total_tasks": {
+                    "type": "number"
+                },
+                "key": {
+                    "description": "Nom de l'équipe ou du rôle, vide pour les utilisateurs sans équipe ou rôle",
+                    "type": "string"
+                },
+                "members": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.Leaderboard": {
+            "type": "object",
+            "properties": {
+                "by": {
+                    "description": "completed ou rate",
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LeaderboardEntry"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "week_end": {
+                    "description": "Dimanche de la semaine",
+                    "type": "string"
+                },
+                "week_start": {
+                    "description": "Lundi de la semaine (YYYY-MM-DD)",
+                    "type": "string"
+                }
+            }
+        },
+        "response.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "ID": {
+                    "type": "string"
+                },
+                "compelted_tasks": {
+                    "type": "integer"
+                },
+                "completed_percent": {
+                    "type": "number"
+                },
+                "current_streak": {
+                    "type": "integer"
+                },
+                "nom": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "total_tasks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.PublicFile": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.PublicLink": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "null : pas d'expiration",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_viewed_at": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "Propriétaire de la ressource",
+                    "type": "string"
+                },
+                "password_protected": {
+                    "type": "boolean"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_name": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Lien à utiliser sans token",
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.PublicLinkRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "null : pas d'expiration",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Vide : lien sans mot de passe",
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 4
+                }
+            }
+        },
+        "response.PublicResource": {
+            "type": "object",
+            "properties": {
+                "download_url": {
+                    "description": "Contenu du fichier, avec le même mot de passe",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "file": {
+                    "$ref": "#/definitions/response.PublicFile"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "task": {
+                    "$ref": "#/definitions/models.Task"
+                }
+            }
+        },
+        "response.QuotaList": {
+            "type": "object",
+            "properties": {
+                "default_quota_bytes": {
+                    "type": "integer"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RoleQuota"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserQuota"
+                    }
+                }
+            }
+        },
+        "response.QuotaRequest": {
+            "type": "object",
+            "required": [
+                "quota_bytes"
+            ],
+            "properties": {
+                "quota_bytes": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "response.ReportJob": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "title": {
+                "kind": {
+                    "description": "overview, tasks ou stats",
+                    "type": "string"
+                },
+                "query": {
+                    "description": "Paramètres de la demande (filter, sort), rejoués à la génération",
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
                     "type": "string"
                 },
                 "updatedAt": {
                     "type": "string"
                 },
                 "user_id": {
+                    "description": "Demandeur",
                     "type": "string"
                 }
             }
         },
-        "models.User": {
+        "response.SavedViewRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "createdAt": {
+                "filter": {
+                    "description": "ex. completed:eq:false,due_date:lt:@now",
                     "type": "string"
                 },
-                "date_naissance": {
+                "group_by": {
+                    "description": "completed, user_id ou project_id",
                     "type": "string"
                 },
-                "email": {
+                "name": {
                     "type": "string"
                 },
-                "files": {
-                    "description": "Foreign key (fichiers)",
+                "shared_with": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.File"
+                        "type": "string"
                     }
                 },
-                "genre": {
+                "sort": {
+                    "description": "ex. -created_at",
                     "type": "string"
-                },
+                }
+            }
+        },
+        "response.SearchResult": {
+            "type": "object",
+            "properties": {
                 "id": {
                     "type": "string"
                 },
-                "nom": {
+                "rank": {
+                    "type": "number"
+                },
+                "snippet": {
+                    "description": "Extrait en HTML échappé, avec les termes trouvés entre \u003cmark\u003e\u003c/mark\u003e",
                     "type": "string"
                 },
-                "password": {
+                "title": {
                     "type": "string"
                 },
-                "prenom": {
+                "type": {
+                    "description": "task, user ou file",
                     "type": "string"
+                }
+            }
+        },
+        "response.ShareRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "permission": {
+                    "description": "read par défaut",
+                    "type": "string",
+                    "enum": [
+                        "read",
+                        "edit"
+                    ]
                 },
-                "role": {
+                "user_id": {
+                    "description": "Destinataire du partage",
                     "type": "string"
+                }
+            }
+        },
+        "response.SharedByMe": {
+            "type": "object",
+            "properties": {
+                "public_links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.PublicLink"
+                    }
                 },
-                "tasks": {
-                    "description": "Foreign key (taches)",
+                "shares": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Task"
+                        "$ref": "#/definitions/response.SharedResource"
                     }
+                }
+            }
+        },
+        "response.SharedResource": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "updatedAt": {
+                "owner_id": {
+                    "description": "Propriétaire de la ressource",
+                    "type": "string"
+                },
+                "permission": {
+                    "description": "read ou edit",
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_name": {
+                    "description": "Nom du fichier ou titre de la tâche",
+                    "type": "string"
+                },
+                "resource_type": {
+                    "description": "file ou task",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Destinataire du partage",
                     "type": "string"
                 }
             }
         },
-        "response.CompletionRate": {
+        "response.StorageUsage": {
             "type": "object",
             "properties": {
-                "completion_rate": {
+                "file_count": {
+                    "type": "integer"
+                },
+                "quota_bytes": {
+                    "description": "null : illimité",
+                    "type": "integer"
+                },
+                "quota_source": {
+                    "description": "user, role, default ou none",
+                    "type": "string"
+                },
+                "remaining_bytes": {
+                    "type": "integer"
+                },
+                "used_bytes": {
+                    "description": "Somme des tailles des fichiers et de leurs anciennes versions",
+                    "type": "integer"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "response.LoginRequest": {
+        "response.TaskAnalytics": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "from": {
+                    "description": "Début du premier intervalle (YYYY-MM-DD)",
                     "type": "string"
                 },
-                "password": {
+                "group_by": {
+                    "description": "none, user ou project",
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "day, week ou month",
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AnalyticsSeries"
+                    }
+                },
+                "timezone": {
+                    "description": "Fuseau IANA dans lequel les intervalles sont découpés",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Dernier jour inclus (YYYY-MM-DD)",
+                    "type": "string"
+                }
+            }
+        },
+        "response.TrashedFile": {
+            "type": "object",
+            "properties": {
+                "URL": {
+                    "description": "Accessible via HTTP",
+                    "type": "string"
+                },
+                "checksum": {
+                    "description": "SHA-256 du contenu (Blob)",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "description": "Type détecté à partir du contenu",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Local path au niveau du projet (anciens fichiers)",
+                    "type": "string"
+                },
+                "preview_error": {
                     "type": "string"
+                },
+                "preview_status": {
+                    "description": "pending, ready, failed ou unsupported",
+                    "type": "string"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "purge_at": {
+                    "type": "string"
+                },
+                "scan_signature": {
+                    "description": "Menace détectée",
+                    "type": "string"
+                },
+                "scan_status": {
+                    "description": "Analyse antivirus de la version courante",
+                    "type": "string"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "text_error": {
+                    "type": "string"
+                },
+                "text_status": {
+                    "description": "Extraction du texte : pending, ready, failed ou unsupported",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Numéro de la version courante",
+                    "type": "integer"
                 }
             }
         },
         "response.UpdateUser": {
             "type": "object",
             "properties": {
+                "leaderboard_opt_out": {
+                    "description": "Absent : inchangé ; true : l'utilisateur n'apparaît plus dans les classements",
+                    "type": "boolean"
+                },
                 "nom": {
                     "type": "string"
                 },
                 "prenom": {
                     "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Fuseau IANA, ex. Europe/Paris",
+                    "type": "string"
+                }
+            }
+        },
+        "response.UserStat": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "type": "boolean"
+                },
+                "computed_at": {
+                    "description": "Date du calcul (plus ancienne si la valeur vient du cache)",
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "storage_stored_bytes": {
+                    "description": "Octets réellement stockés après déduplication",
+                    "type": "integer"
+                },
+                "storage_used_bytes": {
+                    "description": "Somme des tailles des fichiers (quotas)",
+                    "type": "integer"
+                },
+                "total_completed": {
+                    "type": "integer"
+                },
+                "total_files": {
+                    "type": "integer"
+                },
+                "total_tasks": {
+                    "type": "integer"
+                },
+                "total_user": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.UserStreak": {
+            "type": "object",
+            "properties": {
+                "current_streak": {
+                    "description": "En cours si le dernier jour est aujourd'hui ou hier",
+                    "type": "integer"
+                },
+                "last_completed_day": {
+                    "description": "YYYY-MM-DD, vide si aucune tâche complétée",
+                    "type": "string"
+                },
+                "longest_streak": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
@@ -0,0 +1,192 @@
+// Extraction du texte des documents (PDF et DOCX) pour la recherche plein texte
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnsupported = errors.New("extract: type de document non supporté")
+	ErrTooLarge    = errors.New("extract: document trop volumineux")
+)
+
+// Taille maximale du texte conservé : PostgreSQL limite un tsvector à 1 Mo
+const MaxText = 512 << 10
+
+// Taille maximale de word/document.xml une fois décompressé, contre les archives piégées
+const maxDocumentXML = 64 << 20
+
+var (
+	// Durée maximale d'une extraction, lecture du document comprise (EXTRACT_TIMEOUT)
+	Timeout = 2 * time.Minute
+
+	// Taille maximale d'un DOCX en octets (EXTRACT_MAX_DOCX_SIZE) : il est copié sur disque pour être ouvert
+	MaxDocxSize int64 = 50 << 20
+)
+
+// Load lit EXTRACT_TIMEOUT (durée Go, ex. 2m) et EXTRACT_MAX_DOCX_SIZE
+func Load() error {
+	if value := strings.TrimSpace(os.Getenv("EXTRACT_TIMEOUT")); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("extract: EXTRACT_TIMEOUT %q invalide", value)
+		}
+		Timeout = d
+	}
+	if value := strings.TrimSpace(os.Getenv("EXTRACT_MAX_DOCX_SIZE")); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("extract: EXTRACT_MAX_DOCX_SIZE %q invalide", value)
+		}
+		MaxDocxSize = n
+	}
+	return nil
+}
+
+const docxType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// Supported indique si le texte peut être extrait pour ce type MIME
+func Supported(mimeType string) bool {
+	return mimeType == "application/pdf" || mimeType == docxType
+}
+
+// Text extrait le texte du document r selon son type MIME
+func Text(ctx context.Context, mimeType string, r io.Reader) (string, error) {
+	var text string
+	var err error
+	switch mimeType {
+	case "application/pdf":
+		text, err = pdfText(ctx, r)
+	case docxType:
+		text, err = docxText(r)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+	return clean(text), nil
+}
+
+// Texte d'un PDF avec pdftotext (poppler-utils)
+func pdfText(ctx context.Context, r io.Reader) (string, error) {
+	f, err := os.CreateTemp("", "extract-*.pdf")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "pdftotext", "-enc", "UTF-8", "-q", f.Name(), "-")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("pdftotext: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Texte d'un DOCX : contenu des éléments w:t de word/document.xml, un paragraphe (w:p) par ligne.
+// L'archive est copiée dans un fichier temporaire (au plus MaxDocxSize octets) plutôt qu'en mémoire
+func docxText(r io.Reader) (string, error) {
+	f, err := os.CreateTemp("", "extract-*.docx")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	size, err := io.Copy(f, io.LimitReader(r, MaxDocxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if size > MaxDocxSize {
+		return "", ErrTooLarge
+	}
+	archive, err := zip.NewReader(f, size)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range archive.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		doc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer doc.Close()
+		return wordText(io.LimitReader(doc, maxDocumentXML))
+	}
+	return "", errors.New("extract: word/document.xml absent")
+}
+
+func wordText(r io.Reader) (string, error) {
+	var b strings.Builder
+	decoder := xml.NewDecoder(r)
+	inText := false
+	for b.Len() < MaxText {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteByte('\t')
+			case "br":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// Texte valide pour PostgreSQL (UTF-8, sans NUL), tronqué à MaxText
+func clean(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\x00", "")
+	text = strings.TrimSpace(text)
+	if len(text) > MaxText {
+		cut := MaxText
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+	return text
+}
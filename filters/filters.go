@@ -0,0 +1,327 @@
+// Langage commun de filtrage et de tri pour les endpoints de liste.
+//
+//	?filter=completed:eq:true,created_at:gt:2025-01-01&sort=-created_at,title
+//
+// Chaque condition a la forme champ:opérateur:valeur. Pour "in" et "between"
+// les valeurs sont séparées par "|" (ex. role:in:admin|user). Dans "sort",
+// un "-" devant le champ inverse l'ordre. Les dates acceptent aussi des valeurs
+// relatives : @now, @today, avec un décalage optionnel (@today-7d, @now+2h ;
+// le "+" peut être laissé non encodé), et les IDs la valeur @me (l'utilisateur connecté).
+// Seuls les champs déclarés dans la
+// Spec du modèle sont acceptés : la saisie n'est jamais injectée dans le SQL.
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FieldType int
+
+const (
+	String FieldType = iota
+	Bool
+	Int
+	Time
+	UUID
+	Float
+)
+
+// Field décrit un champ filtrable : la colonne SQL et le type de ses valeurs
+type Field struct {
+	Column string
+	Type   FieldType
+}
+
+// Spec liste les champs autorisés d'un modèle (nom public -> colonne)
+type Spec map[string]Field
+
+var TaskSpec = Spec{
+	"id":           {"tasks.id", UUID},
+	"title":        {"tasks.title", String},
+	"description":  {"tasks.description", String},
+	"completed":    {"tasks.completed", Bool},
+	"completed_at": {"tasks.completed_at", Time},
+	"due_date":     {"tasks.due_date", Time},
+	"user_id":      {"tasks.user_id", UUID},
+	"project_id":   {"tasks.project_id", UUID},
+	"created_at":   {"tasks.created_at", Time},
+	"updated_at":   {"tasks.updated_at", Time},
+}
+
+var UserSpec = Spec{
+	"id":             {"users.id", UUID},
+	"nom":            {"users.nom", String},
+	"prenom":         {"users.prenom", String},
+	"email":          {"users.email", String},
+	"genre":          {"users.genre", String},
+	"role":           {"users.role", String},
+	"team":           {"users.team", String},
+	"date_naissance": {"users.date_naiss", Time},
+	"created_at":     {"users.created_at", Time},
+	"updated_at":     {"users.updated_at", Time},
+}
+
+var FileSpec = Spec{
+	"id":         {"files.id", UUID},
+	"file_name":  {"files.file_name", String},
+	"file_type":  {"files.file_type", String},
+	"file_size":  {"files.size", Int},
+	"user_id":    {"files.user_id", UUID},
+	"created_at": {"files.created_at", Time},
+	"updated_at": {"files.updated_at", Time},
+}
+
+// Résumé d'activité par utilisateur : les colonnes sont celles de la requête agrégée
+// (sous-requête "activity" jointe aux utilisateurs), utilisables dans WHERE et ORDER BY
+var ActivitySpec = Spec{
+	"id":              {"users.id", UUID},
+	"nom":             {"users.nom", String},
+	"role":            {"users.role", String},
+	"team":            {"users.team", String},
+	"total_tasks":     {"COALESCE(activity.total, 0)", Int},
+	"completed_tasks": {"COALESCE(activity.completed, 0)", Int},
+	"completion_rate": {"COALESCE(activity.completed * 100.0 / NULLIF(activity.total, 0), 0)", Float},
+}
+
+// Résumé anonymisé : sans id ni nom, qui permettraient de retrouver un utilisateur masqué
+var AnonymousActivitySpec = Spec{
+	"role":            ActivitySpec["role"],
+	"team":            ActivitySpec["team"],
+	"total_tasks":     ActivitySpec["total_tasks"],
+	"completed_tasks": ActivitySpec["completed_tasks"],
+	"completion_rate": ActivitySpec["completion_rate"],
+}
+
+// ParseError est renvoyé dans le champ "error" de la réponse, d'où les tags JSON
+type ParseError struct {
+	Param  string `json:"param"`
+	Expr   string `json:"expr"`
+	Reason string `json:"reason"`
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %q: %s", e.Param, e.Expr, e.Reason)
+}
+
+type condition struct {
+	field Field
+	op    string
+	args  []any
+}
+
+type sortField struct {
+	column string
+	desc   bool
+}
+
+// Query est le résultat du parsing, appliqué à une requête GORM avec Scope
+type Query struct {
+	conditions []condition
+	sort       []sortField
+}
+
+// FromContext lit les paramètres "filter" et "sort" de la requête
+func FromContext(c *gin.Context, spec Spec) (*Query, error) {
+	me, _ := c.Get("user_id")
+	meID, _ := me.(uuid.UUID)
+	return ParseAs(spec, c.Query("filter"), c.Query("sort"), meID)
+}
+
+func Parse(spec Spec, filter, sort string) (*Query, error) {
+	return ParseAs(spec, filter, sort, uuid.Nil)
+}
+
+// ParseAs est comme Parse mais résout @me avec l'utilisateur donné
+func ParseAs(spec Spec, filter, sort string, me uuid.UUID) (*Query, error) {
+	q := &Query{}
+
+	if filter != "" {
+		for _, expr := range strings.Split(filter, ",") {
+			cond, err := parseCondition(spec, expr, me)
+			if err != nil {
+				return nil, err
+			}
+			q.conditions = append(q.conditions, cond)
+		}
+	}
+
+	if sort != "" {
+		for _, expr := range strings.Split(sort, ",") {
+			name, desc := strings.CutPrefix(strings.TrimSpace(expr), "-")
+			field, ok := spec[name]
+			if !ok {
+				return nil, &ParseError{Param: "sort", Expr: expr, Reason: "champ non triable"}
+			}
+			q.sort = append(q.sort, sortField{column: field.Column, desc: desc})
+		}
+	}
+
+	return q, nil
+}
+
+func parseCondition(spec Spec, expr string, me uuid.UUID) (condition, error) {
+	fail := func(reason string) (condition, error) {
+		return condition{}, &ParseError{Param: "filter", Expr: expr, Reason: reason}
+	}
+
+	//La valeur peut elle-même contenir ":" (ex. une heure), d'où SplitN
+	parts := strings.SplitN(strings.TrimSpace(expr), ":", 3)
+	if len(parts) != 3 {
+		return fail("format attendu champ:opérateur:valeur")
+	}
+	name, op, raw := parts[0], parts[1], parts[2]
+
+	field, ok := spec[name]
+	if !ok {
+		return fail("champ non filtrable")
+	}
+
+	var values []string
+	switch op {
+	case "eq", "ne", "lt", "gt":
+		values = []string{raw}
+	case "contains":
+		if field.Type != String {
+			return fail("contains n'est possible que sur un texte")
+		}
+		return condition{field: field, op: op, args: []any{"%" + escapeLike(raw) + "%"}}, nil
+	case "in":
+		values = strings.Split(raw, "|")
+	case "between":
+		values = strings.Split(raw, "|")
+		if len(values) != 2 {
+			return fail("between attend deux valeurs min|max")
+		}
+	default:
+		return fail("opérateur inconnu")
+	}
+
+	args := make([]any, 0, len(values))
+	for _, v := range values {
+		arg, err := convert(field.Type, v, me)
+		if err != nil {
+			return fail("valeur invalide : " + v)
+		}
+		args = append(args, arg)
+	}
+	return condition{field: field, op: op, args: args}, nil
+}
+
+func convert(t FieldType, v string, me uuid.UUID) (any, error) {
+	switch t {
+	case Bool:
+		return strconv.ParseBool(v)
+	case Int:
+		return strconv.ParseInt(v, 10, 64)
+	case Float:
+		return strconv.ParseFloat(v, 64)
+	case Time:
+		//Un "+" non encodé dans l'URL arrive comme une espace (@now+2h, 2025-01-01T10:00:00+02:00)
+		v = strings.ReplaceAll(v, " ", "+")
+		if strings.HasPrefix(v, "@") {
+			return relativeTime(v, time.Now())
+		}
+		if d, err := time.Parse("2006-01-02", v); err == nil {
+			return d, nil
+		}
+		return time.Parse(time.RFC3339, v)
+	case UUID:
+		if v == "@me" {
+			if me == uuid.Nil {
+				return nil, fmt.Errorf("@me sans utilisateur connecté")
+			}
+			return me, nil
+		}
+		return uuid.Parse(v)
+	}
+	return v, nil
+}
+
+// Résout @now / @today avec un décalage optionnel en heures, jours ou semaines
+func relativeTime(v string, now time.Time) (time.Time, error) {
+	base, offset := v, ""
+	if i := strings.IndexAny(v, "+-"); i > 0 {
+		base, offset = v[:i], v[i:]
+	}
+
+	var t time.Time
+	switch base {
+	case "@now":
+		t = now
+	case "@today":
+		y, m, d := now.Date()
+		t = time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	default:
+		return t, fmt.Errorf("date relative inconnue : %s", v)
+	}
+
+	if offset == "" {
+		return t, nil
+	}
+	if len(offset) < 3 {
+		return t, fmt.Errorf("décalage invalide : %s", offset)
+	}
+	n, err := strconv.Atoi(offset[1 : len(offset)-1])
+	if err != nil {
+		return t, err
+	}
+	if offset[0] == '-' {
+		n = -n
+	}
+	switch offset[len(offset)-1] {
+	case 'h':
+		return t.Add(time.Duration(n) * time.Hour), nil
+	case 'd':
+		return t.AddDate(0, 0, n), nil
+	case 'w':
+		return t.AddDate(0, 0, 7*n), nil
+	}
+	return t, fmt.Errorf("unité de décalage inconnue : %s", offset)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// HasSort indique si un tri a été demandé (sinon l'appelant garde son tri par défaut)
+func (q *Query) HasSort() bool {
+	return len(q.sort) > 0
+}
+
+// Scope applique les conditions et le tri : database.DB.Scopes(q.Scope).Find(&tasks)
+func (q *Query) Scope(db *gorm.DB) *gorm.DB {
+	for _, cond := range q.conditions {
+		col := cond.field.Column
+		switch cond.op {
+		case "eq":
+			db = db.Where(col+" = ?", cond.args[0])
+		case "ne":
+			db = db.Where(col+" <> ?", cond.args[0])
+		case "lt":
+			db = db.Where(col+" < ?", cond.args[0])
+		case "gt":
+			db = db.Where(col+" > ?", cond.args[0])
+		case "contains":
+			db = db.Where(col+" ILIKE ?", cond.args[0])
+		case "in":
+			db = db.Where(col+" IN ?", cond.args)
+		case "between":
+			db = db.Where(col+" BETWEEN ? AND ?", cond.args[0], cond.args[1])
+		}
+	}
+	for _, s := range q.sort {
+		if s.desc {
+			db = db.Order(s.column + " DESC")
+		} else {
+			db = db.Order(s.column + " ASC")
+		}
+	}
+	return db
+}
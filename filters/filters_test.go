@@ -0,0 +1,156 @@
+package filters
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseConditions(t *testing.T) {
+	me := uuid.MustParse("6f1c2a3b-4d5e-4f60-8a9b-0c1d2e3f4a5b")
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		spec   Spec
+		filter string
+		want   []condition
+	}{
+		{"eq booléen", TaskSpec, "completed:eq:true", []condition{{TaskSpec["completed"], "eq", []any{true}}}},
+		{"ne texte", TaskSpec, "title:ne:écrire", []condition{{TaskSpec["title"], "ne", []any{"écrire"}}}},
+		{"gt date", TaskSpec, "created_at:gt:2025-01-01", []condition{{TaskSpec["created_at"], "gt", []any{day}}}},
+		{"lt entier", FileSpec, "file_size:lt:1000", []condition{{FileSpec["file_size"], "lt", []any{int64(1000)}}}},
+		{"contains échappé", TaskSpec, "title:contains:50%_a", []condition{{TaskSpec["title"], "contains", []any{`%50\%\_a%`}}}},
+		{"in", FileSpec, "file_type:in:.pdf|.docx", []condition{{FileSpec["file_type"], "in", []any{".pdf", ".docx"}}}},
+		{"between", FileSpec, "file_size:between:10|20", []condition{{FileSpec["file_size"], "between", []any{int64(10), int64(20)}}}},
+		{"@me", TaskSpec, "user_id:eq:@me", []condition{{TaskSpec["user_id"], "eq", []any{me}}}},
+		{"valeur avec deux-points", TaskSpec, "created_at:gt:2025-01-01T10:00:00Z", []condition{{TaskSpec["created_at"], "gt", []any{day.Add(10 * time.Hour)}}}},
+		{"plusieurs conditions", TaskSpec, "completed:eq:false, title:contains:a", []condition{
+			{TaskSpec["completed"], "eq", []any{false}},
+			{TaskSpec["title"], "contains", []any{"%a%"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseAs(tt.spec, tt.filter, "", me)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(q.conditions, tt.want) {
+				t.Errorf("Parse(%q) =\n%v\nattendu\n%v", tt.filter, q.conditions, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		sort   string
+		param  string
+	}{
+		{"champ inconnu", "password:eq:x", "", "filter"},
+		{"opérateur inconnu", "title:like:x", "", "filter"},
+		{"format incomplet", "title:eq", "", "filter"},
+		{"contains sur un booléen", "completed:contains:t", "", "filter"},
+		{"between à une valeur", "created_at:between:2025-01-01", "", "filter"},
+		{"booléen invalide", "completed:eq:peut-être", "", "filter"},
+		{"date invalide", "created_at:gt:hier", "", "filter"},
+		{"UUID invalide", "user_id:eq:abc", "", "filter"},
+		{"@me sans utilisateur", "user_id:eq:@me", "", "filter"},
+		{"date relative inconnue", "created_at:gt:@yesterday", "", "filter"},
+		{"unité inconnue", "created_at:gt:@now-2y", "", "filter"},
+		{"une condition invalide sur deux", "completed:eq:true,nope:eq:1", "", "filter"},
+		{"tri inconnu", "", "-password", "sort"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(TaskSpec, tt.filter, tt.sort)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Parse(%q, %q) = %v, attendu une ParseError", tt.filter, tt.sort, err)
+			}
+			if perr.Param != tt.param {
+				t.Errorf("Param = %q, attendu %q", perr.Param, tt.param)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	q, err := Parse(TaskSpec, "", "-created_at, title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sortField{{"tasks.created_at", true}, {"tasks.title", false}}
+	if !reflect.DeepEqual(q.sort, want) {
+		t.Errorf("sort = %v, attendu %v", q.sort, want)
+	}
+	if !q.HasSort() {
+		t.Error("HasSort = false")
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"@now", now, false},
+		{"@today", today, false},
+		{"@now+2h", now.Add(2 * time.Hour), false},
+		{"@now-2h", now.Add(-2 * time.Hour), false},
+		{"@today-7d", today.AddDate(0, 0, -7), false},
+		{"@today+1w", today.AddDate(0, 0, 7), false},
+		{"@now+h", time.Time{}, true},
+		{"@now+xd", time.Time{}, true},
+		{"@now+2m", time.Time{}, true},
+		{"@tomorrow", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := relativeTime(tt.value, now)
+		if (err != nil) != tt.wantErr || !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("relativeTime(%q) = %v, %v ; attendu %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+// Le "+" d'une URL non encodée devient une espace une fois la requête décodée
+func TestTimePlusDecodedAsSpace(t *testing.T) {
+	tests := []struct {
+		query string
+		want  time.Time
+	}{
+		{"filter=created_at:gt:2025-01-01T10:00:00+02:00", time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{"filter=created_at:gt:2025-01-01T10:00:00%2B02:00", time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		values, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := Parse(TaskSpec, values.Get("filter"), "")
+		if err != nil {
+			t.Fatalf("%s : %v", tt.query, err)
+		}
+		if got := q.conditions[0].args[0].(time.Time); !got.Equal(tt.want) {
+			t.Errorf("%s : %v, attendu %v", tt.query, got, tt.want)
+		}
+	}
+
+	values, _ := url.ParseQuery("filter=created_at:gt:@now+2h")
+	q, err := Parse(TaskSpec, values.Get("filter"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.conditions[0].args[0].(time.Time); time.Until(got) < time.Hour {
+		t.Errorf("@now+2h décodé = %v, attendu dans environ deux heures", got)
+	}
+}
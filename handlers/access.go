@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"projet1/database"
+	"projet1/models"
+	"projet1/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Niveau d'accès d'un utilisateur à une ressource, du plus faible au plus fort
+type accessLevel int
+
+const (
+	accessNone  accessLevel = iota
+	accessRead              //Partage en lecture
+	accessEdit              //Partage en modification
+	accessOwner             //Propriétaire ou admin : suppression et partage
+)
+
+// Niveau d'accès à une ressource : propriétaire, admin ou partage
+func resourceAccess(userID uuid.UUID, resourceType string, resourceID, ownerID uuid.UUID) accessLevel {
+	if ownerID == userID {
+		return accessOwner
+	}
+	return resourceAccessIn(database.DB, userID, isAdmin(userID), resourceType, resourceID, ownerID)
+}
+
+// Comme resourceAccess, dans la connexion db (une transaction en cours) et avec le rôle admin
+// déjà résolu, pour les vérifications répétées d'une opération groupée
+func resourceAccessIn(db *gorm.DB, userID uuid.UUID, admin bool, resourceType string, resourceID, ownerID uuid.UUID) accessLevel {
+	if ownerID == userID || admin {
+		return accessOwner
+	}
+	var share models.Share
+	err := db.Select("permission").
+		Where("resource_type = ? AND resource_id = ? AND user_id = ?", resourceType, resourceID, userID).
+		Limit(1).Find(&share).Error
+	if err != nil {
+		return accessNone
+	}
+	switch share.Permission {
+	case models.PermissionEdit:
+		return accessEdit
+	case models.PermissionRead:
+		return accessRead
+	}
+	return accessNone
+}
+
+func fileAccess(userID uuid.UUID, file models.File) accessLevel {
+	return resourceAccess(userID, models.ResourceFile, file.ID, file.UserID)
+}
+
+func taskAccess(userID uuid.UUID, task models.Task) accessLevel {
+	return resourceAccess(userID, models.ResourceTask, task.ID, task.UserID)
+}
+
+func taskAccessIn(db *gorm.DB, userID uuid.UUID, admin bool, task models.Task) accessLevel {
+	return resourceAccessIn(db, userID, admin, models.ResourceTask, task.ID, task.UserID)
+}
+
+// Un utilisateur peut lire un fichier s'il en est propriétaire, s'il est admin ou si le fichier lui est partagé
+func canAccessFile(userID uuid.UUID, file models.File) bool {
+	return fileAccess(userID, file) >= accessRead
+}
+
+func isAdmin(userID uuid.UUID) bool {
+	var user models.User
+	if err := database.DB.Select("role").First(&user, "id = ?", userID).Error; err != nil {
+		return false
+	}
+	return user.Role == "admin"
+}
+
+// Restreint une requête sur tasks aux tâches possédées par l'utilisateur ou partagées avec lui ; sans effet pour un admin
+func visibleTasks(userID uuid.UUID, admin bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if admin {
+			return db
+		}
+		return db.Where("tasks.user_id = ? OR tasks.id IN (?)", userID,
+			database.DB.Model(&models.Share{}).Select("resource_id").
+				Where("resource_type = ? AND user_id = ?", models.ResourceTask, userID))
+	}
+}
+
+// Restreint une requête sur files aux fichiers possédés par l'utilisateur ou partagés avec lui ; sans effet pour un admin
+func visibleFiles(userID uuid.UUID, admin bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if admin {
+			return db
+		}
+		return db.Where("files.user_id = ? OR files.id IN (?)", userID,
+			database.DB.Model(&models.Share{}).Select("resource_id").
+				Where("resource_type = ? AND user_id = ?", models.ResourceFile, userID))
+	}
+}
+
+// Répond ACCESS_DENIED si l'utilisateur connecté n'est pas admin
+func requireAdmin(c *gin.Context) bool {
+	if isAdmin(utils.CurrentUserID(c)) {
+		return true
+	}
+	utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+	return false
+}
+
+// Fichier du paramètre file_id, si l'utilisateur connecté y a au moins l'accès need ; l'erreur est déjà envoyée si ok est faux
+func loadAccessibleFile(c *gin.Context, need accessLevel) (models.File, bool) {
+	var file models.File
+	fileID, err := uuid.Parse(c.Param("file_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return file, false
+	}
+	if err := database.DB.First(&file, "id = ?", fileID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return file, false
+	}
+	if fileAccess(utils.CurrentUserID(c), file) < need {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return file, false
+	}
+	return file, true
+}
+
+// Tâche du paramètre id, si l'utilisateur connecté y a au moins l'accès need ; l'erreur est déjà envoyée si ok est faux
+func loadAccessibleTask(c *gin.Context, need accessLevel) (models.Task, bool) {
+	var task models.Task
+	taskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return task, false
+	}
+	if err := database.DB.First(&task, "id = ?", taskID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return task, false
+	}
+	if taskAccess(utils.CurrentUserID(c), task) < need {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return task, false
+	}
+	return task, true
+}
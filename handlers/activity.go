@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"projet1/filters"
+	"projet1/models"
+
+	"gorm.io/gorm"
+)
+
+// ActivityQuery renvoie le résumé d'activité de tous les utilisateurs en une seule requête :
+// les tâches sont comptées par utilisateur dans une sous-requête groupée, jointe aux utilisateurs
+// (ceux sans tâche ont 0). Les colonnes de filters.ActivitySpec sont utilisables dans q
+func ActivityQuery(db *gorm.DB, q *filters.Query) *gorm.DB {
+	query := db.Model(&models.User{}).
+		Select("users.id, users.nom, users.leaderboard_opt_out, "+
+			filters.ActivitySpec["total_tasks"].Column+" AS total_tasks, "+
+			filters.ActivitySpec["completed_tasks"].Column+" AS completed_tasks, "+
+			filters.ActivitySpec["completion_rate"].Column+" AS completed_percent").
+		Joins("LEFT JOIN (?) AS activity ON activity.user_id = users.id", activityCounts(db))
+	//Appliqué tout de suite (et non avec Scopes) pour que le tri demandé passe avant le départage
+	query = q.Scope(query)
+	if !q.HasSort() {
+		query = query.Order("users.nom")
+	}
+	//Départage stable entre deux pages
+	return query.Order("users.id")
+}
+
+// Exclut les utilisateurs qui refusent d'apparaître dans les classements et résumés nominatifs
+func excludeOptedOut(db *gorm.DB) *gorm.DB {
+	return db.Where("NOT users.leaderboard_opt_out")
+}
+
+// Tâches et tâches complétées par utilisateur (sous-requête "activity" de filters.ActivitySpec),
+// lues dans les compteurs tenus à jour à chaque écriture plutôt que recomptées
+func activityCounts(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.UserTaskStats{}).Select("user_id, total, completed")
+}
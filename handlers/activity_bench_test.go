@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"projet1/database"
+	"projet1/filters"
+	"projet1/models"
+	"projet1/response"
+	"projet1/utils"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Rôle des utilisateurs créés par ACTIVITY_BENCH_SEED, pour les retrouver et les supprimer
+const benchRole = "activitybench"
+
+// activityPerUser est l'ancienne stratégie (une requête de tâches par utilisateur), conservée pour
+// la comparer à ActivityQuery. Les utilisateurs sont traités par workers goroutines au plus
+// (1 : un par un, 0 : une goroutine par utilisateur)
+func activityPerUser(ctx context.Context, workers int) ([]response.UserResume, error) {
+	var users []models.User
+	if err := database.DB.WithContext(ctx).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	//Chaque goroutine écrit dans sa propre case : pas de canal ni de verrou
+	resumes := make([]response.UserResume, len(users))
+	pool, ctx := utils.NewPool(ctx, workers)
+	for i, user := range users {
+		pool.Go(func(ctx context.Context) error {
+			resume, err := utils.UserOverviewFunc(ctx, user)
+			resumes[i] = resume
+			return err
+		})
+	}
+	if err := pool.Wait(); err != nil {
+		return nil, err
+	}
+	return resumes, nil
+}
+
+// BenchmarkActivityOverview compare les stratégies de calcul du résumé d'activité
+// (/api/users/activity_overview) sur une base de test (TEST_DATABASE_DSN, ignoré si absente) :
+//
+//	TEST_DATABASE_DSN="host=localhost user=... dbname=projet1_test" go test ./handlers -run '^$' -bench Activity -benchmem
+//	ACTIVITY_BENCH_SEED=2000 ACTIVITY_BENCH_TASKS=30 ...  ajoute 2000 utilisateurs de test (supprimés à la fin)
+//	ACTIVITY_BENCH_POOL=10 ...                            limite le pool de connexions comme en production
+//
+// Pour chaque stratégie : temps et allocations par calcul, et attentes d'une connexion libre
+// dans le pool (pool-waits/op : les anciennes stratégies font une requête par utilisateur)
+func BenchmarkActivityOverview(b *testing.B) {
+	db := openBenchDB(b)
+	sqlDB, err := db.DB()
+	if err != nil {
+		b.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(benchEnvInt(b, "ACTIVITY_BENCH_POOL", 10))
+	if n := benchEnvInt(b, "ACTIVITY_BENCH_SEED", 0); n > 0 {
+		seedBenchUsers(b, db, n, benchEnvInt(b, "ACTIVITY_BENCH_TASKS", 20))
+	}
+
+	ctx := context.Background()
+	workers := benchEnvInt(b, "ACTIVITY_BENCH_WORKERS", 8)
+	all, _ := filters.Parse(filters.ActivitySpec, "", "")
+	ranked, _ := filters.Parse(filters.ActivitySpec, "", "-completion_rate")
+	strategies := []struct {
+		name string
+		run  func() error
+	}{
+		{"sequential", func() error {
+			_, err := activityPerUser(ctx, 1)
+			return err
+		}},
+		{fmt.Sprintf("pool-%d", workers), func() error {
+			_, err := activityPerUser(ctx, workers)
+			return err
+		}},
+		{"goroutines", func() error {
+			_, err := activityPerUser(ctx, 0)
+			return err
+		}},
+		{"aggregate", func() error {
+			var resumes []response.UserResume
+			return ActivityQuery(db, all).Find(&resumes).Error
+		}},
+		{"aggregate-page-20", func() error {
+			var resumes []response.UserResume
+			return ActivityQuery(db, ranked).Limit(20).Find(&resumes).Error
+		}},
+	}
+
+	for _, s := range strategies {
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			before := sqlDB.Stats()
+			for i := 0; i < b.N; i++ {
+				if err := s.run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			waits := sqlDB.Stats().WaitCount - before.WaitCount
+			b.ReportMetric(float64(waits)/float64(b.N), "pool-waits/op")
+		})
+	}
+}
+
+// Connexion à la base de test, migrée pour les tables lues par le résumé d'activité ; le benchmark
+// est ignoré sans TEST_DATABASE_DSN
+func openBenchDB(b *testing.B) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		b.Skip("TEST_DATABASE_DSN non défini : pas de base de test")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		b.Fatal(err)
+	}
+	previous := database.DB
+	database.DB = db
+	b.Cleanup(func() { database.DB = previous })
+
+	if err := db.AutoMigrate(&models.User{}, &models.Project{}, &models.Tag{}, &models.Task{}, &models.UserTaskStats{}); err != nil {
+		b.Fatal(err)
+	}
+	if err := database.MigrateTaskStats(); err != nil {
+		b.Fatal(err)
+	}
+	return db
+}
+
+func benchEnvInt(b *testing.B, name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		b.Fatalf("%s %q invalide", name, value)
+	}
+	return n
+}
+
+// Crée n utilisateurs de test avec tasksPerUser tâches chacun, environ la moitié complétées,
+// supprimés définitivement à la fin du benchmark
+func seedBenchUsers(b *testing.B, db *gorm.DB, n, tasksPerUser int) {
+	b.Cleanup(func() {
+		benchUsers := db.Unscoped().Model(&models.User{}).Select("id").Where("role = ?", benchRole)
+		if err := db.Unscoped().Where("user_id IN (?)", benchUsers).Delete(&models.Task{}).Error; err != nil {
+			b.Error("suppression des tâches de test :", err)
+			return
+		}
+		if err := db.Unscoped().Where("role = ?", benchRole).Delete(&models.User{}).Error; err != nil {
+			b.Error("suppression des utilisateurs de test :", err)
+		}
+	})
+
+	users := make([]models.User, n)
+	for i := range users {
+		users[i] = models.User{
+			ID:    uuid.New(),
+			Nom:   fmt.Sprintf("bench-%05d", i),
+			Email: fmt.Sprintf("bench-%05d@activitybench.local", i),
+			Role:  benchRole,
+		}
+	}
+	if err := db.CreateInBatches(&users, 500).Error; err != nil {
+		b.Fatal(err)
+	}
+
+	tasks := make([]models.Task, 0, n*tasksPerUser)
+	for i, user := range users {
+		for j := 0; j < tasksPerUser; j++ {
+			tasks = append(tasks, models.Task{
+				Title:     fmt.Sprintf("tâche %d", j),
+				Completed: (i+j)%2 == 0,
+				UserID:    user.ID,
+			})
+		}
+	}
+	if err := db.CreateInBatches(&tasks, 1000).Error; err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Nombre maximum d'intervalles d'une série (un an jour par jour)
+const maxAnalyticsBuckets = 366
+
+// Nombre d'intervalles renvoyés quand from n'est pas précisé
+const defaultAnalyticsBuckets = 30
+
+// Compte par intervalle et par clé de regroupement, lu depuis la base
+type bucketCount struct {
+	Key    string
+	Bucket string
+	Count  int64
+}
+
+// @Summary Statistiques des tâches dans le temps
+// @Description Nombre de tâches créées et complétées par jour, semaine (commençant le lundi) ou mois, sur une période, pour tous les utilisateurs ou regroupées par utilisateur ou par projet. Les intervalles sont découpés dans le fuseau tz, à défaut celui de l'utilisateur connecté, à défaut UTC. Les intervalles sans tâche valent 0. Hors admin, seules les tâches de l'utilisateur connecté et celles partagées avec lui sont comptées ; user_id (autre que soi) et group_by=user sont réservés aux admins
+// @Tags Statistiques
+// @Security BearerAuth
+// @Produce json
+// @Param		interval		query		string		false		"Taille des intervalles : day (défaut), week ou month"
+// @Param		from			query		string		false		"Premier jour (YYYY-MM-DD), ramené au début de son intervalle. Défaut : 30 intervalles avant to"
+// @Param		to				query		string		false		"Dernier jour inclus (YYYY-MM-DD). Défaut : aujourd'hui"
+// @Param		tz				query		string		false		"Fuseau IANA, ex. Europe/Paris"
+// @Param		group_by		query		string		false		"none (défaut), user ou project"
+// @Param		user_id			query		string		false		"Seulement les tâches de cet utilisateur (@me pour soi)"
+// @Param		project_id		query		string		false		"Seulement les tâches de ce projet"
+// @Success		200 			{object}	response.TaskAnalytics
+// @Failure		400				{object}	utils.AppError 				"Paramètres invalides ou période trop longue"
+// @Failure		403				{object}	utils.AppError 				"Autre utilisateur ou group_by=user sans être admin"
+// @Failure		500				{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/analytics/tasks [get]
+func GetTaskAnalytics(c *gin.Context) {
+	interval := c.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" && interval != "month" {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("interval doit valoir day, week ou month"))
+		return
+	}
+	groupBy := c.DefaultQuery("group_by", "none")
+	var keyColumn string
+	switch groupBy {
+	case "none":
+		keyColumn = "''"
+	case "user":
+		keyColumn = "tasks.user_id::text"
+	case "project":
+		keyColumn = "COALESCE(tasks.project_id::text, '')"
+	default:
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("group_by doit valoir none, user ou project"))
+		return
+	}
+
+	me := utils.CurrentUserID(c)
+	admin := isAdmin(me)
+	if groupBy == "user" && !admin {
+		utils.JSONAppError(c, utils.ErrAccessDenied, errors.New("group_by=user est réservé aux admins"))
+		return
+	}
+
+	loc, err := analyticsLocation(c)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	//Bornes de la période en jours du fuseau : [start, end[
+	today := time.Now().In(loc)
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.ParseInLocation("2006-01-02", raw, loc); err != nil {
+			utils.JSONAppError(c, utils.ErrBadRequest, fmt.Errorf("to invalide : %w", err))
+			return
+		}
+	}
+	end := to.AddDate(0, 0, 1)
+	start := bucketStart(to, interval)
+	for i := 1; i < defaultAnalyticsBuckets; i++ {
+		start = nextBucket(start, interval, -1)
+	}
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.ParseInLocation("2006-01-02", raw, loc)
+		if err != nil {
+			utils.JSONAppError(c, utils.ErrBadRequest, fmt.Errorf("from invalide : %w", err))
+			return
+		}
+		start = bucketStart(from, interval)
+	}
+	if !start.Before(end) {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("from doit précéder to"))
+		return
+	}
+
+	//Libellés de tous les intervalles, pour compléter ceux sans tâche par 0
+	labels := []string{}
+	for t := start; t.Before(end); t = nextBucket(t, interval, 1) {
+		if len(labels) == maxAnalyticsBuckets {
+			utils.JSONAppError(c, utils.ErrBadRequest, fmt.Errorf("période trop longue : %d intervalles au plus", maxAnalyticsBuckets))
+			return
+		}
+		labels = append(labels, t.Format("2006-01-02"))
+	}
+
+	//Hors admin : ses tâches et celles partagées avec soi
+	scope := database.DB.WithContext(c.Request.Context()).Model(&models.Task{}).Scopes(visibleTasks(me, admin))
+	for _, param := range []string{"user_id", "project_id"} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		id := me
+		if param != "user_id" || raw != "@me" {
+			if id, err = uuid.Parse(raw); err != nil {
+				utils.JSONAppError(c, utils.ErrBadRequest, fmt.Errorf("%s invalide : %w", param, err))
+				return
+			}
+		}
+		if param == "user_id" && id != me && !admin {
+			utils.JSONAppError(c, utils.ErrAccessDenied, errors.New("les statistiques d'un autre utilisateur sont réservées aux admins"))
+			return
+		}
+		scope = scope.Where("tasks."+param+" = ?", id)
+	}
+
+	created, err := countByBucket(scope, "tasks.created_at", keyColumn, interval, loc, start, end)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	completed, err := countByBucket(scope, "tasks.completed_at", keyColumn, interval, loc, start, end)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+
+	//Une série par clé rencontrée ; sans regroupement, toujours une série même vide
+	index := make(map[string]int, len(labels))
+	for i, label := range labels {
+		index[label] = i
+	}
+	series := map[string]*response.AnalyticsSeries{}
+	serie := func(key string) *response.AnalyticsSeries {
+		s, ok := series[key]
+		if !ok {
+			s = &response.AnalyticsSeries{
+				Key:       key,
+				Created:   make([]int64, len(labels)),
+				Completed: make([]int64, len(labels)),
+			}
+			series[key] = s
+		}
+		return s
+	}
+	if groupBy == "none" {
+		serie("")
+	}
+	for _, row := range created {
+		s := serie(row.Key)
+		s.Created[index[row.Bucket]] += row.Count
+		s.TotalCreated += row.Count
+	}
+	for _, row := range completed {
+		s := serie(row.Key)
+		s.Completed[index[row.Bucket]] += row.Count
+		s.TotalCompleted += row.Count
+	}
+
+	if err := labelSeries(database.DB, groupBy, series); err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	result := response.TaskAnalytics{
+		Interval: interval,
+		Timezone: loc.String(),
+		From:     start.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		GroupBy:  groupBy,
+		Labels:   labels,
+		Series:   make([]response.AnalyticsSeries, 0, len(series)),
+	}
+	for _, s := range series {
+		result.Series = append(result.Series, *s)
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		a, b := result.Series[i], result.Series[j]
+		if a.Label != b.Label {
+			return a.Label < b.Label
+		}
+		return a.Key < b.Key
+	})
+
+	utils.JSONAppSuccess(c, "Statistiques des tâches", result)
+}
+
+// Fuseau des statistiques : paramètre tz, sinon celui de l'utilisateur connecté, sinon UTC
+func analyticsLocation(c *gin.Context) (*time.Location, error) {
+	name := c.Query("tz")
+	if name == "" {
+		var user models.User
+		if err := database.DB.Select("timezone").First(&user, "id = ?", utils.CurrentUserID(c)).Error; err == nil {
+			name = user.Timezone
+		}
+	}
+	return loadTimezone(name)
+}
+
+// Charge un fuseau IANA ; vide : UTC
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("fuseau horaire inconnu : %s", name)
+	}
+	return loc, nil
+}
+
+// Début de l'intervalle contenant le jour t (les semaines commencent le lundi, comme date_trunc)
+func bucketStart(t time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
+
+// Intervalle suivant (n = 1) ou précédent (n = -1). AddDate garde minuit local lors des changements d'heure
+func nextBucket(t time.Time, interval string, n int) time.Time {
+	switch interval {
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	}
+	return t.AddDate(0, 0, n)
+}
+
+// Compte les tâches par clé et par intervalle de column (created_at ou completed_at),
+// découpé dans le fuseau loc par PostgreSQL
+func countByBucket(scope *gorm.DB, column, keyColumn, interval string, loc *time.Location, start, end time.Time) ([]bucketCount, error) {
+	var rows []bucketCount
+	err := scope.Session(&gorm.Session{}).
+		Select(keyColumn+" AS key, to_char(date_trunc(?, "+column+" AT TIME ZONE ?), 'YYYY-MM-DD') AS bucket, COUNT(*) AS count", interval, loc.String()).
+		Where(column+" >= ? AND "+column+" < ?", start, end).
+		Group("1, 2").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Renseigne la clé publique et le nom affichable de chaque série
+func labelSeries(db *gorm.DB, groupBy string, series map[string]*response.AnalyticsSeries) error {
+	ids := []string{}
+	for key := range series {
+		if key != "" {
+			ids = append(ids, key)
+		}
+	}
+
+	names := map[string]string{}
+	switch groupBy {
+	case "user":
+		var users []models.User
+		if err := db.Select("id, nom, prenom").Where("id IN ?", ids).Find(&users).Error; err != nil {
+			return err
+		}
+		for _, u := range users {
+			names[u.ID.String()] = strings.TrimSpace(u.Prenom + " " + u.Nom)
+		}
+	case "project":
+		var projects []models.Project
+		if err := db.Select("id, name").Where("id IN ?", ids).Find(&projects).Error; err != nil {
+			return err
+		}
+		for _, p := range projects {
+			names[p.ID.String()] = p.Name
+		}
+	}
+
+	for key, s := range series {
+		switch {
+		case key != "":
+			s.Label = names[key]
+		case groupBy == "project":
+			s.Key, s.Label = "none", "Sans projet"
+		default:
+			s.Key, s.Label = "all", "Toutes les tâches"
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/storage"
+	"projet1/uploads"
+	"projet1/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Nombre maximal d'IDs dans une demande d'archive
+const maxArchiveFiles = 1000
+
+// Taille totale maximale des fichiers d'une archive
+var maxArchiveSize int64 = 2 << 30
+
+// loadArchives lit ARCHIVE_MAX_SIZE (taille, ex. "2GB")
+func loadArchives() error {
+	maxArchiveSize = 2 << 30
+	if value := strings.TrimSpace(os.Getenv("ARCHIVE_MAX_SIZE")); value != "" {
+		size, err := uploads.ParseSize(value)
+		if err != nil {
+			return fmt.Errorf("ARCHIVE_MAX_SIZE: %w", err)
+		}
+		maxArchiveSize = size
+	}
+	return nil
+}
+
+// @Summary Télécharger plusieurs fichiers en ZIP
+// @Description Archive ZIP générée à la volée (sans être conservée en mémoire) avec les fichiers demandés : file_ids, ou tous les fichiers de user_id. Chaque fichier doit être accessible (propriétaire, admin ou partage) et ne pas être en quarantaine ; avec user_id, les fichiers en quarantaine sont ignorés. Les noms en double sont renommés "nom (2).ext". La taille totale est limitée par ARCHIVE_MAX_SIZE
+// @Tags Fichier
+// @Security BearerAuth
+// @Accept json
+// @Produce application/zip
+// @Param		archive			body		response.ArchiveRequest		true		"file_ids ou user_id"
+// @Success		200				{file}		string						"Archive ZIP"
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé ou fichier infecté"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable"
+// @Failure		413				{object}	utils.AppError 				"Archive trop volumineuse"
+// @Failure		423				{object}	utils.AppError 				"Fichier en attente d'analyse antivirus"
+// @Router /api/files/archive [post]
+func DownloadArchive(c *gin.Context) {
+	var req response.ArchiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	if (len(req.FileIDs) == 0) == (req.UserID == nil) {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("file_ids ou user_id est obligatoire (pas les deux)"))
+		return
+	}
+	if len(req.FileIDs) > maxArchiveFiles {
+		utils.JSONAppError(c, utils.ErrBadRequest, fmt.Errorf("%d fichiers au maximum", maxArchiveFiles))
+		return
+	}
+
+	me := utils.CurrentUserID(c)
+	files, ok := archiveFiles(c, me, req)
+	if !ok {
+		return
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+	if total > maxArchiveSize {
+		utils.JSONAppError(c, utils.ErrArchiveTooLarge, fmt.Errorf("%d octets demandés, %d au maximum", total, maxArchiveSize))
+		return
+	}
+
+	//À partir d'ici la réponse est envoyée : une erreur ne peut plus que tronquer l'archive
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", utils.ContentDisposition("attachment", "fichiers-"+time.Now().Format("2006-01-02")+".zip"))
+	c.Status(200)
+
+	archive := zip.NewWriter(c.Writer)
+	names := map[string]bool{}
+	for _, file := range files {
+		if err := addToArchive(c, archive, file, uniqueName(names, file.FileName)); err != nil {
+			log.Printf("archive: fichier %s: %v", file.ID, err)
+			c.Abort()
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		log.Println("archive:", err)
+	}
+}
+
+// Fichiers demandés, après vérification des droits ; l'erreur est déjà envoyée si ok est faux
+func archiveFiles(c *gin.Context, me uuid.UUID, req response.ArchiveRequest) ([]models.File, bool) {
+	var files []models.File
+	if req.UserID != nil {
+		if *req.UserID != me && !isAdmin(me) {
+			utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+			return nil, false
+		}
+		err := database.DB.Where("user_id = ? AND scan_status = ?", *req.UserID, models.ScanClean).
+			Order("file_name, created_at").Find(&files).Error
+		if err != nil {
+			utils.JSONAppError(c, utils.ErrInternal, err)
+			return nil, false
+		}
+		return files, true
+	}
+
+	//IDs dédoublonnés, dans l'ordre de la demande
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	for _, id := range req.FileIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	var found []models.File
+	if err := database.DB.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return nil, false
+	}
+	byID := make(map[uuid.UUID]models.File, len(found))
+	for _, file := range found {
+		byID[file.ID] = file
+	}
+
+	admin := isAdmin(me)
+	for _, id := range ids {
+		file, ok := byID[id]
+		if !ok {
+			utils.JSONAppError(c, utils.ErrRecordNotFound, fmt.Errorf("fichier %s introuvable", id))
+			return nil, false
+		}
+		//Mêmes règles que ServeFile : propriétaire, admin ou partage, contenu analysé et sain
+		if file.UserID != me && !admin && !canAccessFile(me, file) {
+			utils.JSONAppError(c, utils.ErrAccessDenied, fmt.Errorf("fichier %s", id))
+			return nil, false
+		}
+		if file.Quarantined() {
+			utils.JSONAppError(c, quarantineAppError(file), fmt.Errorf("fichier %s", id))
+			return nil, false
+		}
+		files = append(files, file)
+	}
+	return files, true
+}
+
+// Copie le contenu d'un fichier dans une nouvelle entrée de l'archive
+func addToArchive(c *gin.Context, archive *zip.Writer, file models.File, name string) error {
+	backend, err := storage.Get(file.StorageBackend)
+	if err != nil {
+		return err
+	}
+	obj, err := backend.Get(c.Request.Context(), file.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: contentModTime(file)}
+	//Formats déjà compressés : les recompresser ne ferait que coûter du temps
+	if compressedType(file.MimeType) {
+		header.Method = zip.Store
+	}
+	w, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, obj)
+	return err
+}
+
+func compressedType(mimeType string) bool {
+	switch {
+	case strings.HasPrefix(mimeType, "image/") && mimeType != "image/bmp" && mimeType != "image/tiff",
+		strings.HasPrefix(mimeType, "application/vnd.openxmlformats-officedocument."),
+		mimeType == "application/vnd.oasis.opendocument.text",
+		mimeType == "application/zip":
+		return true
+	}
+	return false
+}
+
+// Nom d'entrée unique dans l'archive (sans tenir compte de la casse) : "rapport.pdf", "rapport (2).pdf"...
+func uniqueName(used map[string]bool, name string) string {
+	//Les noms sont déjà assainis à l'upload ; un ancien nom vide ou avec des séparateurs est corrigé ici
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" {
+		name = "fichier"
+	}
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"projet1/database"
+	"projet1/models"
+	"projet1/storage"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestUniqueName(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{"noms distincts", []string{"a.txt", "b.txt"}, []string{"a.txt", "b.txt"}},
+		{"doublons", []string{"rapport.pdf", "rapport.pdf", "rapport.pdf"},
+			[]string{"rapport.pdf", "rapport (2).pdf", "rapport (3).pdf"}},
+		{"casse ignorée", []string{"Photo.JPG", "photo.jpg"}, []string{"Photo.JPG", "photo (2).jpg"}},
+		{"nom déjà suffixé", []string{"a (2).txt", "a.txt", "a.txt"}, []string{"a (2).txt", "a.txt", "a (3).txt"}},
+		{"sans extension", []string{"LISEZMOI", "LISEZMOI"}, []string{"LISEZMOI", "LISEZMOI (2)"}},
+		{"plusieurs extensions", []string{"archive.tar.gz", "archive.tar.gz"}, []string{"archive.tar.gz", "archive.tar (2).gz"}},
+		{"séparateurs", []string{"dossier/a.txt", `..\b.txt`}, []string{"dossier_a.txt", ".._b.txt"}},
+		{"nom vide", []string{"", ""}, []string{"fichier", "fichier (2)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := map[string]bool{}
+			got := make([]string, len(tt.files))
+			for i, name := range tt.files {
+				got[i] = uniqueName(used, name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("noms = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressedType(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		want     bool
+	}{
+		{"image/jpeg", true},
+		{"image/png", true},
+		{"image/bmp", false},
+		{"image/tiff", false},
+		{"application/zip", true},
+		{"application/vnd.openxmlformats-officedocument.wordprocessingml.document", true},
+		{"application/vnd.oasis.opendocument.text", true},
+		{"application/pdf", false},
+		{"text/plain", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := compressedType(tt.mimeType); got != tt.want {
+			t.Errorf("compressedType(%q) = %v, attendu %v", tt.mimeType, got, tt.want)
+		}
+	}
+}
+
+// addToArchive recopie le contenu stocké dans l'archive, compressé ou non selon le type
+func TestAddToArchive(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := storage.Backends[local.Name()]
+	storage.Backends[local.Name()] = local
+	t.Cleanup(func() { storage.Backends[local.Name()] = previous })
+
+	//Base en mode DryRun : contentModTime ne trouve pas de version et reprend la date du fichier
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousDB := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previousDB })
+
+	created := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	files := []struct {
+		file       models.File
+		content    string
+		wantMethod uint16
+	}{
+		{models.File{FileName: "notes.txt", MimeType: "text/plain", StorageKey: "notes"}, strings.Repeat("bonjour ", 100), zip.Deflate},
+		{models.File{FileName: "photo.jpg", MimeType: "image/jpeg", StorageKey: "photo"}, "\xff\xd8\xff contenu", zip.Store},
+	}
+	for _, f := range files {
+		if err := local.Put(context.Background(), f.file.StorageKey, strings.NewReader(f.content), int64(len(f.content)), f.file.MimeType); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/api/files/archive", nil)
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, f := range files {
+		f.file.StorageBackend = local.Name()
+		f.file.CreatedAt = created
+		if err := addToArchive(c, archive, f.file, f.file.FileName); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.File) != len(files) {
+		t.Fatalf("%d entrées, attendu %d", len(r.File), len(files))
+	}
+	for i, entry := range r.File {
+		want := files[i]
+		if entry.Name != want.file.FileName || entry.Method != want.wantMethod {
+			t.Errorf("entrée %d : %s (méthode %d), attendu %s (méthode %d)", i, entry.Name, entry.Method, want.file.FileName, want.wantMethod)
+		}
+		if !entry.Modified.Equal(created) {
+			t.Errorf("%s : date %v, attendu %v", entry.Name, entry.Modified, created)
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil || string(got) != want.content {
+			t.Errorf("%s : contenu %q, %v", entry.Name, got, err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"projet1/blobs"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/storage"
+	"projet1/utils"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Une seule vérification à la fois ; le rapport de la dernière reste consultable
+var blobJob struct {
+	sync.Mutex
+	report response.BlobReport
+}
+
+// @Summary Vérifier l'intégrité des fichiers stockés
+// @Description Lance en arrière-plan la relecture de tous les contenus stockés : le SHA-256 est recalculé et comparé, les contenus manquants ou corrompus sont signalés. Les anciens fichiers sans somme de contrôle sont calculés et dédupliqués. Réservé aux admins
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success		202				{object}	response.BlobReport
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		409				{object}	utils.AppError 				"Vérification déjà en cours"
+// @Router /api/admin/blobs/verify [post]
+func VerifyBlobs(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	blobJob.Lock()
+	defer blobJob.Unlock()
+	if blobJob.report.Running {
+		utils.JSONAppError(c, utils.ErrJobRunning, nil)
+		return
+	}
+	now := time.Now()
+	blobJob.report = response.BlobReport{Running: true, StartedAt: &now, Problems: []response.BlobProblem{}}
+
+	go runBlobVerification(context.Background())
+
+	utils.JSONAppSuccessCRUD(c, utils.SuccessJobStarted, blobJob.report)
+}
+
+// @Summary Rapport de vérification des fichiers
+// @Description Résultat de la dernière vérification (ou de celle en cours) : contenus manquants ou corrompus et fichiers concernés. Réservé aux admins
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success		200				{object}	response.BlobReport
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Router /api/admin/blobs/verify [get]
+func GetBlobReport(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	blobJob.Lock()
+	report := blobJob.report
+	blobJob.Unlock()
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, report)
+}
+
+func runBlobVerification(ctx context.Context) {
+	err := backfillChecksums(ctx)
+	if err == nil {
+		err = checkBlobs(ctx)
+	}
+	var problems []response.BlobProblem
+	if err == nil {
+		problems, err = blobProblems()
+	}
+
+	blobJob.Lock()
+	defer blobJob.Unlock()
+	now := time.Now()
+	blobJob.report.Running = false
+	blobJob.report.FinishedAt = &now
+	blobJob.report.Problems = append(blobJob.report.Problems, problems...)
+	if err != nil {
+		log.Println("Erreur lors de la vérification des fichiers:", err)
+		blobJob.report.Error = err.Error()
+	}
+}
+
+// Calcule le SHA-256 des fichiers enregistrés avant les blobs et les rattache au blob
+// correspondant ; si ce contenu existe déjà, l'ancienne copie est supprimée
+func backfillChecksums(ctx context.Context) error {
+	var files []models.File
+	return database.DB.Where("checksum IS NULL OR checksum = ''").FindInBatches(&files, 100, func(tx *gorm.DB, batch int) error {
+		for _, file := range files {
+			sum, size, err := hashObject(ctx, file.StorageBackend, file.StorageKey)
+			if errors.Is(err, storage.ErrNotFound) {
+				addBlobProblem(response.BlobProblem{
+					Status:         models.BlobMissing,
+					StorageBackend: file.StorageBackend,
+					StorageKey:     file.StorageKey,
+					FileIDs:        []uuid.UUID{file.ID},
+				})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			var blob models.Blob
+			err = statsTransaction(func(tx *gorm.DB) error {
+				blob, err = blobs.Acquire(tx, models.Blob{Hash: sum, Size: size, StorageBackend: file.StorageBackend, StorageKey: file.StorageKey})
+				if err != nil {
+					return err
+				}
+				changes := map[string]interface{}{
+					"checksum":        sum,
+					"storage_backend": blob.StorageBackend,
+					"storage_key":     blob.StorageKey,
+				}
+				if err := tx.Model(&models.File{}).Where("id = ?", file.ID).Updates(changes).Error; err != nil {
+					return err
+				}
+				//La version courante pointe vers le même objet que le fichier
+				return tx.Model(&models.FileVersion{}).
+					Where("file_id = ? AND version = ?", file.ID, file.Version).Updates(changes).Error
+			})
+			if err != nil {
+				return err
+			}
+
+			//Contenu déjà stocké pour un autre fichier : l'ancienne copie est inutile
+			if blob.StorageKey != file.StorageKey || blob.StorageBackend != file.StorageBackend {
+				if backend, err := storage.Get(file.StorageBackend); err == nil {
+					backend.Delete(ctx, file.StorageKey)
+				}
+			}
+			blobJob.Lock()
+			blobJob.report.Backfilled++
+			blobJob.Unlock()
+		}
+		return nil
+	}).Error
+}
+
+// Relit chaque blob et enregistre son statut (ok, missing ou corrupt)
+func checkBlobs(ctx context.Context) error {
+	var blobs []models.Blob
+	return database.DB.FindInBatches(&blobs, 100, func(tx *gorm.DB, batch int) error {
+		for _, blob := range blobs {
+			status := models.BlobOK
+			sum, _, err := hashObject(ctx, blob.StorageBackend, blob.StorageKey)
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				status = models.BlobMissing
+			case err != nil:
+				return err
+			case sum != blob.Hash:
+				status = models.BlobCorrupt
+			}
+
+			err = database.DB.Model(&models.Blob{}).Where("hash = ?", blob.Hash).
+				Updates(map[string]interface{}{"status": status, "verified_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+			blobJob.Lock()
+			blobJob.report.Checked++
+			blobJob.Unlock()
+		}
+		return nil
+	}).Error
+}
+
+// Blobs en erreur avec les fichiers qui les utilisent
+func blobProblems() ([]response.BlobProblem, error) {
+	var blobs []models.Blob
+	if err := database.DB.Where("status <> ?", models.BlobOK).Order("hash").Find(&blobs).Error; err != nil {
+		return nil, err
+	}
+	problems := make([]response.BlobProblem, 0, len(blobs))
+	for _, blob := range blobs {
+		problem := response.BlobProblem{
+			Hash:           blob.Hash,
+			Status:         blob.Status,
+			StorageBackend: blob.StorageBackend,
+			StorageKey:     blob.StorageKey,
+		}
+		if err := database.DB.Model(&models.File{}).Where("checksum = ?", blob.Hash).Pluck("id", &problem.FileIDs).Error; err != nil {
+			return nil, err
+		}
+		problems = append(problems, problem)
+	}
+	return problems, nil
+}
+
+func addBlobProblem(problem response.BlobProblem) {
+	blobJob.Lock()
+	blobJob.report.Problems = append(blobJob.report.Problems, problem)
+	blobJob.Unlock()
+}
+
+// SHA-256 (hexadécimal) et taille d'un objet stocké
+func hashObject(ctx context.Context, backendName, key string) (string, int64, error) {
+	//Fichier sans objet associé (ni chemin ni clé)
+	if key == "" {
+		return "", 0, storage.ErrNotFound
+	}
+	backend, err := storage.Get(backendName)
+	if err != nil {
+		return "", 0, err
+	}
+	obj, err := backend.Get(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	defer obj.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, obj)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
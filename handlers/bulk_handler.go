@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"errors"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Nombre maximum de tâches modifiables en une seule opération groupée
+const maxBulkTasks = 500
+
+// Erreur sentinelle utilisée pour annuler la transaction en mode dry-run
+var errDryRun = errors.New("dry run")
+
+// @Summary Opération groupée sur les tâches
+// @Description Appliquer complete, reopen, delete, retag, reassign ou move à une liste d'IDs ou aux tâches correspondant à un filtre, dans une seule transaction. Avec dry_run, rien n'est enregistré et la réponse décrit ce qui changerait. Chaque tâche exige le droit de modification (propriétaire pour delete et reassign) ; les autres sont renvoyées en forbidden. Un filtre vide est réservé aux admins
+// @Tags Tâche
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		request		body		response.BulkTaskRequest		true		"L'action et les tâches ciblées"
+// @Success		200 		{object}	response.BulkTaskResult
+// @Failure		400			{object}	utils.AppError 				"Requête invalide"
+// @Failure		403			{object}	utils.AppError 				"Filtre vide réservé aux admins"
+// @Failure		404			{object}	utils.AppError 				"Utilisateur ou projet introuvable"
+// @Failure		413			{object}	utils.AppError 				"Trop de tâches ciblées"
+// @Failure		422			{object}	utils.AppError 				"Echec de validation"
+// @Failure		500			{object}	utils.AppError 				"Erreur interne"
+// @Router  /api/tasks/bulk [post]
+func BulkTasks(c *gin.Context) {
+	var req response.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	//Il faut cibler les tâches soit par IDs soit par filtre
+	if len(req.IDs) == 0 && req.Filter == nil {
+		utils.JSONAppError(c, utils.ErrValidationFailed, errors.New("ids ou filter est obligatoire"))
+		return
+	}
+
+	me := utils.CurrentUserID(c)
+	admin := isAdmin(me)
+
+	//Un filtre vide cible toutes les tâches de la base
+	if len(req.IDs) == 0 && emptyBulkFilter(req.Filter) && !admin {
+		utils.JSONAppError(c, utils.ErrAccessDenied, errors.New("un filtre vide est réservé aux admins"))
+		return
+	}
+
+	//Vérification des paramètres propres à chaque action
+	switch req.Action {
+	case "reassign":
+		if req.UserID == nil {
+			utils.JSONAppError(c, utils.ErrValidationFailed, errors.New("user_id est obligatoire pour reassign"))
+			return
+		}
+		if err := database.DB.First(&models.User{}, "id = ?", *req.UserID).Error; err != nil {
+			utils.JSONAppError(c, utils.ErrUserNotFound, err)
+			return
+		}
+	case "move":
+		if req.ProjectID != nil {
+			if err := database.DB.First(&models.Project{}, "id = ?", *req.ProjectID).Error; err != nil {
+				utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+				return
+			}
+		}
+	}
+
+	ids, err := resolveBulkTaskIDs(req, me, admin)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	if len(ids) > maxBulkTasks {
+		utils.JSONAppError(c, utils.ErrBulkLimitExceeded, nil)
+		return
+	}
+
+	result := response.BulkTaskResult{
+		Action:  req.Action,
+		DryRun:  req.DryRun,
+		Matched: len(ids),
+		Items:   []response.BulkItemResult{},
+	}
+
+	err = statsTransaction(func(tx *gorm.DB) error {
+		var tags []models.Tag
+		if req.Action == "retag" {
+			var err error
+			if tags, err = findOrCreateTags(tx, req.Tags); err != nil {
+				return err
+			}
+		}
+
+		for _, id := range ids {
+			item, err := applyBulkTaskAction(tx, req, me, admin, id, tags)
+			if err != nil {
+				return err
+			}
+			if item.Status == "updated" || item.Status == "deleted" {
+				result.Changed++
+			}
+			result.Items = append(result.Items, item)
+		}
+
+		//En mode dry-run on annule tout ce qui a été appliqué
+		if req.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+
+	utils.JSONAppSuccess(c, "Opération groupée sur les tâches", result)
+}
+
+// Vrai si le filtre ne contient aucun critère
+func emptyBulkFilter(f *response.BulkTaskFilter) bool {
+	return f.UserID == nil && f.ProjectID == nil && f.Completed == nil &&
+		strings.TrimSpace(f.Tag) == "" && f.CreatedAfter == nil && f.CreatedBefore == nil
+}
+
+// Récupère les IDs ciblés : la liste explicite (sans doublons) ou le résultat du filtre,
+// limité aux tâches visibles par l'utilisateur s'il n'est pas admin
+func resolveBulkTaskIDs(req response.BulkTaskRequest, me uuid.UUID, admin bool) ([]uuid.UUID, error) {
+	if len(req.IDs) > 0 {
+		seen := make(map[uuid.UUID]bool, len(req.IDs))
+		ids := make([]uuid.UUID, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	f := req.Filter
+	query := database.DB.Model(&models.Task{}).Scopes(visibleTasks(me, admin))
+	if f.UserID != nil {
+		query = query.Where("tasks.user_id = ?", *f.UserID)
+	}
+	if f.ProjectID != nil {
+		query = query.Where("tasks.project_id = ?", *f.ProjectID)
+	}
+	if f.Completed != nil {
+		query = query.Where("tasks.completed = ?", *f.Completed)
+	}
+	if f.Tag != "" {
+		query = query.Joins("JOIN task_tags ON task_tags.task_id = tasks.id").
+			Joins("JOIN tags ON tags.id = task_tags.tag_id").
+			Where("tags.name = ?", strings.TrimSpace(f.Tag))
+	}
+	if f.CreatedAfter != nil {
+		query = query.Where("tasks.created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		query = query.Where("tasks.created_at <= ?", *f.CreatedBefore)
+	}
+
+	//On lit une ligne de plus que la limite pour détecter le dépassement
+	var ids []uuid.UUID
+	err := query.Order("tasks.id").Limit(maxBulkTasks+1).Pluck("tasks.id", &ids).Error
+	return ids, err
+}
+
+// Applique l'action sur une tâche. Chaque tâche a son propre savepoint, pris avant toute
+// lecture, pour qu'une erreur n'annule pas les autres éléments de la transaction.
+// L'erreur renvoyée est fatale : le savepoint n'a pas pu être posé ou restauré
+func applyBulkTaskAction(tx *gorm.DB, req response.BulkTaskRequest, me uuid.UUID, admin bool, id uuid.UUID, tags []models.Tag) (response.BulkItemResult, error) {
+	item := response.BulkItemResult{ID: id}
+
+	if err := tx.SavePoint("bulk_item").Error; err != nil {
+		return item, err
+	}
+	fail := func(status string, err error) (response.BulkItemResult, error) {
+		item.Status = status
+		if err != nil {
+			item.Error = err.Error()
+		}
+		return item, tx.RollbackTo("bulk_item").Error
+	}
+
+	var task models.Task
+	if err := tx.First(&task, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fail("not_found", nil)
+		}
+		return fail("failed", err)
+	}
+
+	//Suppression et réattribution sont réservées au propriétaire, comme pour DeleteTask et UpdateTask
+	need := accessEdit
+	if req.Action == "delete" || req.Action == "reassign" {
+		need = accessOwner
+	}
+	if taskAccessIn(tx, me, admin, task) < need {
+		return fail("forbidden", errors.New(utils.ErrAccessDenied.Message))
+	}
+
+	var err error
+	item.Status = "updated"
+	switch req.Action {
+	case "complete", "reopen":
+		completed := req.Action == "complete"
+		if task.Completed == completed {
+			item.Status = "unchanged"
+		} else {
+			var completedAt *time.Time
+			if completed {
+				now := time.Now()
+				completedAt = &now
+			}
+			err = tx.Model(&task).Updates(map[string]any{"completed": completed, "completed_at": completedAt}).Error
+		}
+	case "delete":
+		item.Status = "deleted"
+		if err = deleteResourceShares(tx, models.ResourceTask, task.ID); err == nil {
+			err = tx.Delete(&task).Error
+		}
+	case "reassign":
+		if task.UserID == *req.UserID {
+			item.Status = "unchanged"
+		} else {
+			err = tx.Model(&task).Update("user_id", *req.UserID).Error
+		}
+	case "move":
+		if sameProject(task.ProjectID, req.ProjectID) {
+			item.Status = "unchanged"
+		} else {
+			err = tx.Model(&task).Update("project_id", req.ProjectID).Error
+		}
+	case "retag":
+		var current []models.Tag
+		if err = tx.Model(&task).Association("Tags").Find(&current); err == nil {
+			if sameTags(current, tags) {
+				item.Status = "unchanged"
+			} else {
+				err = tx.Model(&task).Association("Tags").Replace(tags)
+			}
+		}
+	}
+
+	if err != nil {
+		return fail("failed", err)
+	}
+	return item, nil
+}
+
+// Retrouve ou crée les étiquettes par leur nom
+func findOrCreateTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var tag models.Tag
+		if err := tx.Where(models.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func sameProject(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func sameTags(a, b []models.Tag) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	names := make(map[string]bool, len(a))
+	for _, t := range a {
+		names[t.Name] = true
+	}
+	for _, t := range b {
+		if !names[t.Name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"projet1/models"
+	"projet1/response"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEmptyBulkFilter(t *testing.T) {
+	id := uuid.New()
+	completed := false
+	now := time.Now()
+	tests := []struct {
+		name   string
+		filter response.BulkTaskFilter
+		want   bool
+	}{
+		{"aucun critère", response.BulkTaskFilter{}, true},
+		{"étiquette blanche", response.BulkTaskFilter{Tag: "  "}, true},
+		{"utilisateur", response.BulkTaskFilter{UserID: &id}, false},
+		{"projet", response.BulkTaskFilter{ProjectID: &id}, false},
+		{"completed à false", response.BulkTaskFilter{Completed: &completed}, false},
+		{"étiquette", response.BulkTaskFilter{Tag: "urgent"}, false},
+		{"créées après", response.BulkTaskFilter{CreatedAfter: &now}, false},
+		{"créées avant", response.BulkTaskFilter{CreatedBefore: &now}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emptyBulkFilter(&tt.filter); got != tt.want {
+				t.Errorf("emptyBulkFilter = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// La liste explicite est dédoublonnée dans l'ordre, sans requête en base
+func TestResolveBulkTaskIDsExplicit(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	tests := []struct {
+		name string
+		ids  []uuid.UUID
+		want []uuid.UUID
+	}{
+		{"sans doublon", []uuid.UUID{a, b, c}, []uuid.UUID{a, b, c}},
+		{"doublons", []uuid.UUID{a, b, a, c, b}, []uuid.UUID{a, b, c}},
+		{"un seul ID répété", []uuid.UUID{c, c, c}, []uuid.UUID{c}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveBulkTaskIDs(response.BulkTaskRequest{IDs: tt.ids}, uuid.New(), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveBulkTaskIDs = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameProject(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	aCopy := a
+	tests := []struct {
+		name string
+		x, y *uuid.UUID
+		want bool
+	}{
+		{"deux nil", nil, nil, true},
+		{"nil et projet", nil, &a, false},
+		{"projet et nil", &a, nil, false},
+		{"même projet", &a, &aCopy, true},
+		{"projets différents", &a, &b, false},
+	}
+	for _, tt := range tests {
+		if got := sameProject(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s : sameProject = %v, attendu %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSameTags(t *testing.T) {
+	tags := func(names ...string) []models.Tag {
+		var list []models.Tag
+		for _, name := range names {
+			list = append(list, models.Tag{Name: name})
+		}
+		return list
+	}
+	tests := []struct {
+		name string
+		x, y []models.Tag
+		want bool
+	}{
+		{"vides", nil, tags(), true},
+		{"mêmes étiquettes", tags("a", "b"), tags("a", "b"), true},
+		{"ordre différent", tags("a", "b"), tags("b", "a"), true},
+		{"une de plus", tags("a"), tags("a", "b"), false},
+		{"même nombre, noms différents", tags("a", "b"), tags("a", "c"), false},
+	}
+	for _, tt := range tests {
+		if got := sameTags(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s : sameTags = %v, attendu %v", tt.name, got, tt.want)
+		}
+	}
+}
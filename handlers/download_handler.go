@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/storage"
+	"projet1/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Durée de validité maximale d'un lien de téléchargement
+const maxDownloadLinkTTL = 24 * time.Hour
+
+// @Summary Générer un lien de téléchargement
+// @Description Générer un lien signé (HMAC) et temporaire vers un fichier. Le lien peut être utilisé sans token jusqu'à son expiration ou sa révocation
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Param		ttl				query		int				false		"Durée de validité en secondes (15 minutes par défaut, 24h maximum)"
+// @Success		201				{object}	response.DownloadLink
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable"
+// @Router /api/users/file_link/{file_id} [post]
+func CreateDownloadLink(c *gin.Context) {
+	fileID, err := uuid.Parse(c.Param("file_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	ttl, err := downloadLinkTTL(c.Query("ttl"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	var file models.File
+	if err := database.DB.First(&file, "id = ?", fileID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+
+	me := utils.CurrentUserID(c)
+	if !canAccessFile(me, file) {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return
+	}
+
+	link := models.DownloadLink{
+		FileID:    file.ID,
+		UserID:    me,
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	}
+	if err := database.DB.Create(&link).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+
+	expires := link.ExpiresAt.Unix()
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordCreated, response.DownloadLink{
+		ID:        link.ID,
+		URL:       fmt.Sprintf("/download/%s?exp=%d&sig=%s", link.ID, expires, utils.SignDownload(link.ID, file.ID, expires)),
+		ExpiresAt: link.ExpiresAt,
+	})
+}
+
+// @Summary Révoquer un lien de téléchargement
+// @Description Révoquer un lien de téléchargement avant son expiration (créateur du lien, propriétaire du fichier ou admin)
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Param		link_id			path		string			true		"ID du lien"
+// @Success		200				{object}	utils.AppSuccessCRUD
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Lien introuvable"
+// @Router /api/users/file_link/{link_id} [delete]
+func RevokeDownloadLink(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("link_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	var link models.DownloadLink
+	if err := database.DB.First(&link, "id = ?", linkID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+
+	me := utils.CurrentUserID(c)
+	if link.UserID != me {
+		var file models.File
+		if err := database.DB.First(&file, "id = ?", link.FileID).Error; err != nil || fileAccess(me, file) < accessOwner {
+			utils.JSONAppError(c, utils.ErrAccessDenied, err)
+			return
+		}
+	}
+
+	if err := database.DB.Model(&link).Update("revoked_at", time.Now()).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, link)
+}
+
+// @Summary Télécharger un fichier avec un lien signé
+// @Description Télécharger un fichier avec un lien généré par /api/users/file_link. La signature, l'expiration, la révocation et les droits du créateur du lien sont vérifiés
+// @Tags Fichier
+// @Produce octet-stream
+// @Param		link_id			path		string			true		"ID du lien"
+// @Param		exp				query		int				true		"Expiration (timestamp Unix)"
+// @Param		sig				query		string			true		"Signature"
+// @Param		inline			query		bool			false		"Afficher dans le navigateur au lieu de télécharger"
+// @Param		Range			header		string			false		"Plage d'octets (ex. bytes=0-1023)"
+// @Success		200				{file}		string						"File Content"
+// @Success		206				{file}		string						"Partial Content"
+// @Success		304				{string}	string						"Not Modified"
+// @Failure		403				{object}	utils.AppError 				"Signature invalide ou accès refusé"
+// @Failure		410				{object}	utils.AppError 				"Lien expiré ou révoqué"
+// @Router /download/{link_id} [get]
+func DownloadFile(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("link_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidSignature, err)
+		return
+	}
+	expires, err := strconv.ParseInt(c.Query("exp"), 10, 64)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidSignature, err)
+		return
+	}
+
+	var link models.DownloadLink
+	if err := database.DB.First(&link, "id = ?", linkID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidSignature, nil)
+		return
+	}
+	if !utils.VerifyDownload(link.ID, link.FileID, expires, c.Query("sig")) || expires != link.ExpiresAt.Unix() {
+		utils.JSONAppError(c, utils.ErrInvalidSignature, nil)
+		return
+	}
+	if time.Now().Unix() > expires || link.RevokedAt != nil {
+		utils.JSONAppError(c, utils.ErrLinkExpired, nil)
+		return
+	}
+
+	var file models.File
+	if err := database.DB.First(&file, "id = ?", link.FileID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+
+	//Le créateur du lien doit toujours avoir accès au fichier
+	if !canAccessFile(link.UserID, file) {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return
+	}
+
+	serveStoredFile(c, file)
+}
+
+// Envoie le contenu d'un fichier depuis le backend qui le contient, en flux (le contenu
+// n'est jamais chargé en mémoire) avec le nom d'origine, les validateurs de cache et la
+// prise en charge des requêtes conditionnelles (304, 412) et partielles (Range, If-Range)
+func serveStoredFile(c *gin.Context, file models.File) {
+	//Contenu en quarantaine : analyse antivirus en attente ou menace détectée
+	if file.Quarantined() {
+		utils.JSONAppError(c, quarantineAppError(file), nil)
+		return
+	}
+	backend, err := storage.Get(file.StorageBackend)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	obj, err := backend.Get(c.Request.Context(), file.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	defer obj.Close()
+
+	//Les anciens fichiers n'ont pas de type détecté : on se rabat sur l'extension
+	contentType := file.MimeType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(file.FileType)
+	}
+	if contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	//Somme de contrôle du contenu (absente pour les fichiers pas encore vérifiés)
+	if file.Checksum != "" {
+		c.Header("ETag", `"`+file.Checksum+`"`)
+		if sum, err := hex.DecodeString(file.Checksum); err == nil {
+			c.Header("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum))
+		}
+	}
+	disposition := "attachment"
+	if inline, _ := strconv.ParseBool(c.Query("inline")); inline {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", utils.ContentDisposition(disposition, file.FileName))
+	c.Header("Cache-Control", "private, no-cache")
+
+	http.ServeContent(c.Writer, c.Request, file.FileName, contentModTime(file), obj)
+}
+
+// Date de Last-Modified : création de la version servie (le fichier lui-même est modifié
+// par les traitements en arrière-plan sans que son contenu change)
+func contentModTime(file models.File) time.Time {
+	var version models.FileVersion
+	err := database.DB.Select("created_at").Where("file_id = ? AND version = ?", file.ID, file.Version).Limit(1).Find(&version).Error
+	if err != nil || version.CreatedAt.IsZero() {
+		return file.CreatedAt
+	}
+	return version.CreatedAt
+}
+
+// Durée demandée (en secondes) ou DOWNLOAD_LINK_TTL, 15 minutes par défaut
+func downloadLinkTTL(value string) (time.Duration, error) {
+	if value == "" {
+		value = os.Getenv("DOWNLOAD_LINK_TTL")
+	}
+	if value == "" {
+		return 15 * time.Minute, nil
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0, errors.New("ttl invalide")
+	}
+	return min(time.Duration(seconds)*time.Second, maxDownloadLinkTTL), nil
+}
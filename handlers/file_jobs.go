@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"projet1/database"
+	"projet1/extract"
+	"projet1/jobs"
+	"projet1/models"
+	"projet1/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Traitements en arrière-plan des fichiers
+const (
+	scanJob    = "scan"
+	previewJob = "preview"
+	textJob    = "text"
+)
+
+// StartFileJobs enregistre les traitements des fichiers et relance ceux restés en attente
+// (fichiers existants, redémarrage pendant un traitement)
+func StartFileJobs(ctx context.Context) {
+	jobs.Register(scanJob, scanFile)
+	jobs.Register(previewJob, generatePreview)
+	jobs.Register(textJob, extractText)
+
+	go func() {
+		pending := []struct {
+			kind, column string
+			query        *gorm.DB
+		}{
+			{scanJob, "file_id", database.DB.Model(&models.FileVersion{}).Distinct().
+				Where("scan_status IN ?", []string{models.ScanPending, models.ScanFailed})},
+			{previewJob, "id", database.DB.Model(&models.File{}).
+				Where("preview_status = ? AND scan_status = ?", models.ProcessingPending, models.ScanClean)},
+			{textJob, "id", database.DB.Model(&models.File{}).
+				Where("text_status = ? AND scan_status = ?", models.ProcessingPending, models.ScanClean)},
+		}
+		for _, p := range pending {
+			var ids []uuid.UUID
+			if err := p.query.Pluck(p.column, &ids).Error; err != nil {
+				log.Println("Erreur lors de la reprise des traitements de fichiers:", err)
+				return
+			}
+			for _, id := range ids {
+				if err := jobs.EnqueueWait(ctx, p.kind, id); err != nil {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Demande les traitements de la version courante : l'analyse antivirus d'abord, l'aperçu
+// et l'extraction du texte une fois le contenu sain (scanFile les relance)
+func queueFileJobs(file models.File) {
+	if file.Quarantined() {
+		jobs.Enqueue(scanJob, file.ID)
+		return
+	}
+	jobs.Enqueue(previewJob, file.ID)
+	jobs.Enqueue(textJob, file.ID)
+}
+
+// extractText enregistre le texte de la version courante d'un PDF ou d'un DOCX. Comme pour
+// les aperçus, rien n'est enregistré si une nouvelle version a remplacé celle traitée
+func extractText(ctx context.Context, fileID uuid.UUID) error {
+	var file models.File
+	if err := database.DB.First(&file, "id = ?", fileID).Error; err != nil {
+		return err
+	}
+	if file.TextStatus != models.ProcessingPending || file.Quarantined() {
+		return nil
+	}
+	if !extract.Supported(file.MimeType) {
+		return setFileText(file, models.ProcessingUnsupported, "", nil)
+	}
+
+	text, err := readText(ctx, file)
+	if err != nil {
+		status := models.ProcessingFailed
+		if errors.Is(err, extract.ErrUnsupported) {
+			status = models.ProcessingUnsupported
+		}
+		if uerr := setFileText(file, status, err.Error(), nil); uerr != nil {
+			return uerr
+		}
+		return err
+	}
+	return setFileText(file, models.ProcessingReady, "", &models.FileText{FileID: file.ID, Version: file.Version, Content: text})
+}
+
+// Lit le texte de la version courante, en EXTRACT_TIMEOUT au plus (pdftotext peut boucler sur un PDF malformé)
+func readText(ctx context.Context, file models.File) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, extract.Timeout)
+	defer cancel()
+
+	backend, err := storage.Get(file.StorageBackend)
+	if err != nil {
+		return "", err
+	}
+	obj, err := backend.Get(ctx, file.StorageKey)
+	if err != nil {
+		return "", err
+	}
+	defer obj.Close()
+	return extract.Text(ctx, file.MimeType, obj)
+}
+
+// Enregistre le statut de l'extraction et le texte (remplacé par celui de la nouvelle
+// version) si le fichier est toujours à la version traitée
+func setFileText(file models.File, status, message string, text *models.FileText) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.File{}).Where("id = ? AND version = ?", file.ID, file.Version).
+			Updates(map[string]interface{}{"text_status": status, "text_error": message})
+		if res.Error != nil || res.RowsAffected == 0 || text == nil {
+			return res.Error
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(text).Error
+	})
+}
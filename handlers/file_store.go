@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"projet1/blobs"
+	"projet1/models"
+	"projet1/storage"
+	"projet1/uploads"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoadFiles lit la configuration propre aux fichiers stockés, à appeler au démarrage
+func LoadFiles() error {
+	for _, load := range []func() error{loadDefaultQuota, loadVersions, loadArchives, loadTrash} {
+		if err := load(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Nombre de versions conservées par fichier ; 0 : illimité
+var maxVersions = 10
+
+// loadVersions lit FILE_MAX_VERSIONS
+func loadVersions() error {
+	maxVersions = 10
+	if value := strings.TrimSpace(os.Getenv("FILE_MAX_VERSIONS")); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("FILE_MAX_VERSIONS %q invalide", value)
+		}
+		maxVersions = n
+	}
+	return nil
+}
+
+// Compte les octets lus pendant l'envoi vers le stockage
+type byteCounter int64
+
+func (n *byteCounter) Write(p []byte) (int, error) {
+	*n += byteCounter(len(p))
+	return len(p), nil
+}
+
+// storeFile envoie le contenu vers le stockage en calculant son SHA-256 au fil de l'eau,
+// puis l'enregistre si le quota du propriétaire le permet : nouveau models.File quand
+// target est nil (avec l'ID fileID, ou un nouvel ID si fileID est uuid.Nil), sinon nouvelle
+// version courante de target (userID est alors l'auteur).
+// Un contenu déjà présent n'est gardé qu'une fois : la version pointe vers le blob
+// existant et la copie qui vient d'être envoyée est supprimée
+func storeFile(ctx context.Context, r io.Reader, size int64, checked uploads.Result, userID, fileID uuid.UUID, target *models.File) (models.File, error) {
+	backend := storage.Default
+	objectID := uuid.New()
+	key := objectID.String() + checked.Extension
+
+	hash := sha256.New()
+	var n byteCounter
+	if err := backend.Put(ctx, key, io.TeeReader(r, io.MultiWriter(hash, &n)), size, checked.MimeType); err != nil {
+		return models.File{}, err
+	}
+	if int64(n) != size {
+		backend.Delete(ctx, key)
+		return models.File{}, fmt.Errorf("taille reçue %d différente de la taille annoncée %d", n, size)
+	}
+
+	version := models.FileVersion{
+		FileName:       checked.FileName,
+		FileType:       checked.Extension,
+		MimeType:       checked.MimeType,
+		Size:           size,
+		Checksum:       hex.EncodeToString(hash.Sum(nil)),
+		StorageBackend: backend.Name(),
+		StorageKey:     key,
+		UserID:         userID,
+		ScanStatus:     models.ScanPending, //En quarantaine jusqu'à l'analyse antivirus (queueFileJobs)
+	}
+
+	if fileID == uuid.Nil {
+		fileID = objectID
+	}
+	file := models.File{ID: fileID, URL: "/api/users/get_file/" + fileID.String(), UserID: userID}
+	if target != nil {
+		file = models.File{ID: target.ID, UserID: target.UserID}
+	}
+
+	var pruned []models.Blob
+	err := statsTransaction(func(tx *gorm.DB) error {
+		//Vérification définitive du quota, sous verrou
+		if err := checkQuota(tx, file.UserID, size, true); err != nil {
+			return err
+		}
+		blob, err := blobs.Acquire(tx, models.Blob{
+			Hash:           version.Checksum,
+			Size:           size,
+			StorageBackend: version.StorageBackend,
+			StorageKey:     version.StorageKey,
+		})
+		if err != nil {
+			return err
+		}
+		version.StorageBackend, version.StorageKey = blob.StorageBackend, blob.StorageKey
+
+		pruned, err = saveVersion(tx, &file, target != nil, &version)
+		return err
+	})
+	if err != nil {
+		backend.Delete(ctx, key)
+		return models.File{}, err
+	}
+
+	//Contenu déjà connu : la nouvelle copie est inutile
+	if version.StorageKey != key || version.StorageBackend != backend.Name() {
+		if err := backend.Delete(ctx, key); err != nil {
+			log.Printf("fichier %s: suppression de la copie en double impossible: %v", file.ID, err)
+		}
+	}
+	blobs.DeleteObjects(ctx, pruned)
+	queueFileJobs(file)
+	return file, nil
+}
+
+// saveVersion enregistre version comme version courante de file : file est créé, ou relu
+// sous verrou quand il existe déjà et la version prend le numéro suivant.
+// Les versions au-delà de FILE_MAX_VERSIONS sont supprimées ; les objets qui ne sont plus
+// utilisés sont renvoyés pour être effacés du stockage après la transaction
+func saveVersion(tx *gorm.DB, file *models.File, exists bool, version *models.FileVersion) ([]models.Blob, error) {
+	//L'aperçu et le texte de la nouvelle version sont générés après la transaction (queueFileJobs)
+	if !exists {
+		version.Version = 1
+		version.Apply(file)
+		file.ResetDerived()
+		if err := tx.Create(file).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(file, "id = ?", file.ID).Error; err != nil {
+			return nil, err
+		}
+		version.Version = file.Version + 1
+		version.Apply(file)
+		file.ResetDerived()
+		if err := tx.Save(file).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	version.FileID = file.ID
+	if err := tx.Create(version).Error; err != nil {
+		return nil, err
+	}
+	return pruneVersions(tx, file.ID)
+}
+
+// Supprime les versions les plus anciennes au-delà de maxVersions (la version courante est toujours la plus récente)
+func pruneVersions(tx *gorm.DB, fileID uuid.UUID) ([]models.Blob, error) {
+	if maxVersions <= 0 {
+		return nil, nil
+	}
+	var old []models.FileVersion
+	err := tx.Where("file_id = ?", fileID).Order("version DESC").Offset(maxVersions).Find(&old).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []models.Blob
+	for _, v := range old {
+		if err := tx.Delete(&v).Error; err != nil {
+			return nil, err
+		}
+		blob, err := blobs.Release(tx, v)
+		if err != nil {
+			return nil, err
+		}
+		if blob != nil {
+			unused = append(unused, *blob)
+		}
+	}
+	return unused, nil
+}
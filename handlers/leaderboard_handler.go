@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"projet1/database"
+	"projet1/filters"
+	"projet1/models"
+	"projet1/response"
+	"projet1/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Taille maximale du classement
+const maxLeaderboard = 100
+
+// Taille minimale d'un groupe pour publier ses moyennes : en dessous, elles décriraient une personne identifiable
+const minGroupMembers = 3
+
+// Fuseau de chaque utilisateur pour découper les jours (UTC s'il n'en a pas choisi)
+const userTimezone = "COALESCE(NULLIF(users.timezone, ''), 'UTC')"
+
+// Séries de jours consécutifs avec au moins une tâche complétée, par utilisateur (user_id, current_streak,
+// longest_streak, last_completed_day). Les jours consécutifs ont le même day - rang : chaque groupe est une série.
+// Une série est en cours si son dernier jour est aujourd'hui ou hier (la journée n'est pas finie)
+func streaksQuery(db *gorm.DB) *gorm.DB {
+	return db.Raw(`WITH days AS (
+		SELECT DISTINCT tasks.user_id,
+			(tasks.completed_at AT TIME ZONE ` + userTimezone + `)::date AS day,
+			(now() AT TIME ZONE ` + userTimezone + `)::date AS today
+		FROM tasks JOIN users ON users.id = tasks.user_id
+		WHERE tasks.completed_at IS NOT NULL AND tasks.deleted_at IS NULL
+	), runs AS (
+		SELECT user_id, today, MAX(day) AS last_day, COUNT(*) AS length
+		FROM (SELECT *, day - (ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY day))::int AS grp FROM days) AS ranked
+		GROUP BY user_id, today, grp
+	)
+	SELECT user_id,
+		COALESCE(MAX(length) FILTER (WHERE last_day >= today - 1), 0) AS current_streak,
+		MAX(length) AS longest_streak,
+		to_char(MAX(last_day), 'YYYY-MM-DD') AS last_completed_day
+	FROM runs GROUP BY user_id`)
+}
+
+// @Summary Séries de jours productifs
+// @Description Série en cours et plus longue série de jours consécutifs avec au moins une tâche complétée, dans le fuseau de l'utilisateur. Celle d'un utilisateur qui refuse les classements n'est visible que par lui et les admins
+// @Tags Statistiques
+// @Security BearerAuth
+// @Produce json
+// @Param		user_id		query		string		false		"Utilisateur (défaut : l'utilisateur connecté)"
+// @Success		200 		{object}	response.UserStreak
+// @Failure		400			{object}	utils.AppError 				"ID invalide"
+// @Failure		403			{object}	utils.AppError 				"Accès refusé"
+// @Failure		404			{object}	utils.AppError 				"Utilisateur introuvable"
+// @Failure		500			{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/analytics/streaks [get]
+func GetUserStreak(c *gin.Context) {
+	me := utils.CurrentUserID(c)
+	userID := me
+	if raw := c.Query("user_id"); raw != "" {
+		var err error
+		if userID, err = uuid.Parse(raw); err != nil {
+			utils.JSONAppError(c, utils.ErrBadRequest, err)
+			return
+		}
+	}
+
+	var user models.User
+	if err := database.DB.Select("id, leaderboard_opt_out").First(&user, "id = ?", userID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrUserNotFound, err)
+		return
+	}
+	if user.LeaderboardOptOut && userID != me && !isAdmin(me) {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return
+	}
+
+	streak := response.UserStreak{UserID: userID}
+	err := database.DB.WithContext(c.Request.Context()).
+		Table("(?) AS streaks", streaksQuery(database.DB)).
+		Where("user_id = ?", userID).
+		Scan(&streak).Error
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, streak)
+}
+
+// @Summary Classement de la semaine
+// @Description Classement des utilisateurs par tâches complétées pendant la semaine (by=completed) ou par taux de complétion de la semaine (by=rate) : tâches complétées parmi celles à traiter (créées avant la fin de la semaine et pas complétées avant son début). Les ex-aequo ont le même rang ; les utilisateurs qui refusent les classements n'apparaissent pas
+// @Tags Statistiques
+// @Security BearerAuth
+// @Produce json
+// @Param		by			query		string		false		"completed (défaut) ou rate"
+// @Param		week		query		string		false		"Un jour de la semaine (YYYY-MM-DD), défaut : cette semaine"
+// @Param		tz			query		string		false		"Fuseau IANA des semaines (défaut : celui de l'utilisateur connecté, sinon UTC)"
+// @Param		limit		query		int			false		"Nombre de lignes (défaut 10, max 100)"
+// @Param		filter		query		string		false		"Filtre sur les utilisateurs (ex. team:eq:support,role:eq:user)"
+// @Success		200 		{object}	response.Leaderboard
+// @Failure		400			{object}	utils.AppError 				"Paramètres invalides"
+// @Failure		500			{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/analytics/leaderboard [get]
+func GetLeaderboard(c *gin.Context) {
+	by := c.DefaultQuery("by", "completed")
+	if by != "completed" && by != "rate" {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("by doit valoir completed ou rate"))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxLeaderboard {
+		utils.JSONAppError(c, utils.ErrBadRequest, fmt.Errorf("limit doit être compris entre 1 et %d", maxLeaderboard))
+		return
+	}
+	q, err := filters.Parse(filters.UserSpec, c.Query("filter"), "")
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidFilter, err)
+		return
+	}
+	loc, err := analyticsLocation(c)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	//Semaine du lundi 0h au lundi suivant 0h, dans le fuseau demandé
+	day := time.Now().In(loc)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	if raw := c.Query("week"); raw != "" {
+		if day, err = time.ParseInLocation("2006-01-02", raw, loc); err != nil {
+			utils.JSONAppError(c, utils.ErrBadRequest, fmt.Errorf("week invalide : %w", err))
+			return
+		}
+	}
+	start := bucketStart(day, "week")
+	end := nextBucket(start, "week", 1)
+
+	db := database.DB.WithContext(c.Request.Context())
+	week := db.Model(&models.Task{}).
+		Select("user_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE completed_at >= ? AND completed_at < ?) AS completed", start, end).
+		Where("created_at < ? AND (completed_at IS NULL OR completed_at >= ?)", end, start).
+		Group("user_id")
+	rate := "week.completed * 100.0 / week.total"
+	order := "week.completed DESC, " + rate + " DESC"
+	if by == "rate" {
+		order = rate + " DESC, week.completed DESC"
+	}
+
+	query := db.Model(&models.User{}).
+		Select("RANK() OVER (ORDER BY "+order+") AS rank, users.id, users.nom, users.role, users.team, "+
+			"week.total AS total_tasks, week.completed AS completed_tasks, "+rate+" AS completed_percent, "+
+			"COALESCE(streaks.current_streak, 0) AS current_streak").
+		Joins("JOIN (?) AS week ON week.user_id = users.id", week).
+		Joins("LEFT JOIN (?) AS streaks ON streaks.user_id = users.id", streaksQuery(db)).
+		Where("NOT users.leaderboard_opt_out").
+		Where("week.completed > 0")
+	query = q.Scope(query)
+
+	board := response.Leaderboard{
+		By:        by,
+		WeekStart: start.Format("2006-01-02"),
+		WeekEnd:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Timezone:  loc.String(),
+		Entries:   []response.LeaderboardEntry{},
+	}
+	if err := query.Order("rank, users.nom, users.id").Limit(limit).Scan(&board.Entries).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccess(c, "Classement de la semaine", board)
+}
+
+// @Summary Moyennes par équipe ou par rôle
+// @Description Nombre de membres et moyennes par utilisateur : tâches, tâches complétées, taux de complétion et série en cours. Les utilisateurs qui refusent les classements ne sont pas comptés, et les groupes de moins de 3 membres ne sont pas renvoyés
+// @Tags Statistiques
+// @Security BearerAuth
+// @Produce json
+// @Param		group_by	query		string		false		"team (défaut) ou role"
+// @Param		filter		query		string		false		"Filtre sur les utilisateurs avant regroupement : role, team, total_tasks, completed_tasks, completion_rate (ex. role:eq:user)"
+// @Success		200 		{array}		response.GroupAverage
+// @Failure		400			{object}	utils.AppError 				"Paramètres invalides"
+// @Failure		500			{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/analytics/averages [get]
+func GetGroupAverages(c *gin.Context) {
+	groupBy := c.DefaultQuery("group_by", "team")
+	if groupBy != "team" && groupBy != "role" {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("group_by doit valoir team ou role"))
+		return
+	}
+	//Pas de filtre sur id ni nom : un groupe réduit à un utilisateur choisi donnerait ses chiffres
+	q, err := filters.Parse(filters.AnonymousActivitySpec, c.Query("filter"), "")
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidFilter, err)
+		return
+	}
+
+	averages, err := groupAverages(database.DB.WithContext(c.Request.Context()), groupBy, q)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, averages)
+}
+
+// Moyennes par valeur de users.<groupBy> (team ou role) des utilisateurs sélectionnés par q, hors
+// utilisateurs qui refusent les classements ; les groupes de moins de minGroupMembers sont omis
+func groupAverages(db *gorm.DB, groupBy string, q *filters.Query) ([]response.GroupAverage, error) {
+	key := "COALESCE(users." + groupBy + ", '')"
+	query := db.Model(&models.User{}).
+		Select(key+" AS key, COUNT(*) AS members, "+
+			"AVG("+filters.ActivitySpec["total_tasks"].Column+") AS avg_total_tasks, "+
+			"AVG("+filters.ActivitySpec["completed_tasks"].Column+") AS avg_completed_tasks, "+
+			"AVG("+filters.ActivitySpec["completion_rate"].Column+") AS avg_completion_rate, "+
+			"AVG(COALESCE(streaks.current_streak, 0)) AS avg_current_streak").
+		Joins("LEFT JOIN (?) AS activity ON activity.user_id = users.id", activityCounts(db)).
+		Joins("LEFT JOIN (?) AS streaks ON streaks.user_id = users.id", streaksQuery(db)).
+		Scopes(excludeOptedOut)
+	query = q.Scope(query)
+
+	averages := []response.GroupAverage{}
+	err := query.Group(key).Having("COUNT(*) >= ?", minGroupMembers).Order(key).Scan(&averages).Error
+	return averages, err
+}
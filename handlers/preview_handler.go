@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"projet1/blobs"
+	"projet1/database"
+	"projet1/models"
+	"projet1/previews"
+	"projet1/storage"
+	"projet1/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// @Summary Aperçu d'un fichier
+// @Description Vignette PNG d'une image ou première page d'un PDF, pour la version courante du fichier. L'aperçu est généré en arrière-plan après l'upload : 202 tant qu'il n'est pas prêt (réessayer après Retry-After). Réponse cachable, 304 si If-None-Match correspond
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce png
+// @Param		file_id			path		string			true		"File ID"
+// @Success		200				{file}		string						"Aperçu PNG"
+// @Success		202				{object}	utils.AppSuccessCRUD		"Aperçu en cours de génération"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé ou fichier infecté"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable ou sans aperçu"
+// @Failure		423				{object}	utils.AppError 				"Fichier en attente d'analyse antivirus"
+// @Router /api/files/{file_id}/preview [get]
+func GetFilePreview(c *gin.Context) {
+	file, ok := loadAccessibleFile(c, accessRead)
+	if !ok {
+		return
+	}
+
+	//Même règle que le téléchargement : l'aperçu n'est servi qu'une fois le contenu déclaré sain
+	if file.Quarantined() {
+		utils.JSONAppError(c, quarantineAppError(file), nil)
+		return
+	}
+
+	switch file.PreviewStatus {
+	case models.ProcessingReady:
+	case models.ProcessingPending:
+		c.Header("Retry-After", "5")
+		utils.JSONAppSuccessCRUD(c, utils.SuccessPreviewPending, nil)
+		return
+	default:
+		utils.JSONAppError(c, utils.ErrPreviewUnavailable, errors.New(file.PreviewError))
+		return
+	}
+
+	//L'aperçu d'une version ne change jamais : sa clé suffit comme ETag
+	etag := fmt.Sprintf(`"%s-v%d"`, file.ID, file.Version)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, max-age=86400")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	backend, err := storage.Get(file.PreviewBackend)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	obj, err := backend.Get(c.Request.Context(), file.PreviewKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		utils.JSONAppError(c, utils.ErrPreviewUnavailable, err)
+		return
+	}
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	defer obj.Close()
+
+	c.DataFromReader(http.StatusOK, -1, previews.ContentType, obj, nil)
+}
+
+// generatePreview produit l'aperçu de la version courante du fichier et l'enregistre dans le
+// stockage. Le statut n'est mis à jour que si le fichier est toujours à cette version :
+// l'aperçu d'une version remplacée entre-temps est abandonné
+func generatePreview(ctx context.Context, fileID uuid.UUID) error {
+	var file models.File
+	if err := database.DB.First(&file, "id = ?", fileID).Error; err != nil {
+		return err
+	}
+	//Un fichier en quarantaine n'est traité qu'après l'analyse antivirus
+	if file.PreviewStatus != models.ProcessingPending || file.Quarantined() {
+		return nil
+	}
+	if !previews.Supported(file.MimeType) {
+		return setPreview(file, map[string]interface{}{"preview_status": models.ProcessingUnsupported})
+	}
+
+	data, err := renderPreview(ctx, file)
+	if err != nil {
+		status := models.ProcessingFailed
+		if errors.Is(err, previews.ErrUnsupported) {
+			status = models.ProcessingUnsupported
+		}
+		if uerr := setPreview(file, map[string]interface{}{"preview_status": status, "preview_error": err.Error()}); uerr != nil {
+			return uerr
+		}
+		return err
+	}
+
+	backend := storage.Default
+	key := fmt.Sprintf("previews/%s-v%d.png", file.ID, file.Version)
+	if err := backend.Put(ctx, key, bytes.NewReader(data), int64(len(data)), previews.ContentType); err != nil {
+		return err
+	}
+	res := database.DB.Model(&models.File{}).Where("id = ? AND version = ?", file.ID, file.Version).
+		Updates(map[string]interface{}{
+			"preview_status":  models.ProcessingReady,
+			"preview_error":   "",
+			"preview_backend": backend.Name(),
+			"preview_key":     key,
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		//Nouvelle version entre-temps : cet aperçu ne sert plus
+		backend.Delete(ctx, key)
+		return res.Error
+	}
+
+	//Aperçu de la version précédente
+	if file.PreviewKey != "" && (file.PreviewKey != key || file.PreviewBackend != backend.Name()) {
+		blobs.DeleteObjects(ctx, []models.Blob{{StorageBackend: file.PreviewBackend, StorageKey: file.PreviewKey}})
+	}
+	return nil
+}
+
+// Lit le contenu de la version courante et en génère l'aperçu
+func renderPreview(ctx context.Context, file models.File) ([]byte, error) {
+	backend, err := storage.Get(file.StorageBackend)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := backend.Get(ctx, file.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	return previews.Generate(ctx, file.MimeType, obj)
+}
+
+// Met à jour l'aperçu si le fichier est toujours à la version traitée
+func setPreview(file models.File, changes map[string]interface{}) error {
+	return database.DB.Model(&models.File{}).Where("id = ? AND version = ?", file.ID, file.Version).Updates(changes).Error
+}
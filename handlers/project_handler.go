@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"projet1/database"
+	"projet1/models"
+	"projet1/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Créer un projet
+// @Description Création d'un projet pour regrouper des tâches
+// @Tags Projet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		project		body		models.Project		true		"Les données du projet à créer"
+// @Success		201			{object}	utils.AppSuccessCRUD
+// @Failure		400			{object}	utils.AppError 				"Requête invalide"
+// @Failure		500			{object}	utils.AppError 				"Erreur interne"
+// @Router /api/projects/ [post]
+func CreateProject(c *gin.Context) {
+	var project models.Project
+	if err := c.ShouldBindJSON(&project); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	if err := database.DB.Create(&project).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordCreated, project)
+}
+
+// @Summary Extraire les projets
+// @Description Extraire tous les projets
+// @Tags Projet
+// @Security BearerAuth
+// @Produce json
+// @Success		200			{object}	utils.AppSuccessCRUD
+// @Failure		500			{object}	utils.AppError 				"Erreur interne"
+// @Router /api/projects/ [get]
+func GetProjects(c *gin.Context) {
+	var projects []models.Project
+	if err := database.DB.Find(&projects).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, projects)
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/uploads"
+	"projet1/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errQuotaExceeded = errors.New("quota de stockage dépassé")
+
+// Quota de stockage des utilisateurs sans quota propre ni quota de rôle ; 0 : illimité
+var defaultQuota int64
+
+// loadDefaultQuota lit STORAGE_QUOTA_DEFAULT (taille, ex. "5GB")
+func loadDefaultQuota() error {
+	defaultQuota = 0
+	if value := strings.TrimSpace(os.Getenv("STORAGE_QUOTA_DEFAULT")); value != "" {
+		quota, err := uploads.ParseSize(value)
+		if err != nil {
+			return fmt.Errorf("STORAGE_QUOTA_DEFAULT: %w", err)
+		}
+		defaultQuota = quota
+	}
+	return nil
+}
+
+// @Summary Mon espace de stockage
+// @Description Espace utilisé par les fichiers de l'utilisateur connecté et quota applicable (propre, du rôle ou par défaut)
+// @Tags Quota
+// @Security BearerAuth
+// @Produce json
+// @Success		200				{object}	response.StorageUsage
+// @Failure		500				{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/me/storage [get]
+func GetMyStorage(c *gin.Context) {
+	usage, err := storageUsage(database.DB, utils.CurrentUserID(c))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, usage)
+}
+
+// @Summary Lister les quotas
+// @Description Quota par défaut, quotas par rôle et quotas propres aux utilisateurs. Réservé aux admins
+// @Tags Quota
+// @Security BearerAuth
+// @Produce json
+// @Success		200				{object}	response.QuotaList
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Router /api/admin/quotas [get]
+func GetQuotas(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	list := response.QuotaList{Roles: []models.RoleQuota{}, Users: []models.UserQuota{}}
+	if defaultQuota > 0 {
+		list.DefaultBytes = &defaultQuota
+	}
+	if err := database.DB.Order("role").Find(&list.Roles).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	if err := database.DB.Order("user_id").Find(&list.Users).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, list)
+}
+
+// @Summary Stockage d'un utilisateur
+// @Description Espace utilisé et quota applicable pour un utilisateur. Réservé aux admins
+// @Tags Quota
+// @Security BearerAuth
+// @Produce json
+// @Param		user_id			path		string			true		"User ID"
+// @Success		200				{object}	response.StorageUsage
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Utilisateur introuvable"
+// @Router /api/admin/quotas/users/{user_id} [get]
+func GetUserStorage(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	userID, ok := quotaUser(c)
+	if !ok {
+		return
+	}
+	usage, err := storageUsage(database.DB, userID)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, usage)
+}
+
+// @Summary Définir le quota d'un utilisateur
+// @Description Quota propre à un utilisateur en octets, prioritaire sur celui de son rôle. Réservé aux admins
+// @Tags Quota
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		user_id			path		string					true		"User ID"
+// @Param		quota			body		response.QuotaRequest	true		"Quota en octets"
+// @Success		200				{object}	response.StorageUsage
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Utilisateur introuvable"
+// @Router /api/admin/quotas/users/{user_id} [put]
+func SetUserQuota(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	userID, ok := quotaUser(c)
+	if !ok {
+		return
+	}
+	var req response.QuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	quota := models.UserQuota{UserID: userID, QuotaBytes: *req.QuotaBytes}
+	if err := database.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&quota).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	usage, err := storageUsage(database.DB, userID)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, usage)
+}
+
+// @Summary Supprimer le quota d'un utilisateur
+// @Description L'utilisateur revient au quota de son rôle ou au quota par défaut. Réservé aux admins
+// @Tags Quota
+// @Security BearerAuth
+// @Produce json
+// @Param		user_id			path		string			true		"User ID"
+// @Success		200				{object}	response.StorageUsage
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Utilisateur introuvable"
+// @Router /api/admin/quotas/users/{user_id} [delete]
+func DeleteUserQuota(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	userID, ok := quotaUser(c)
+	if !ok {
+		return
+	}
+	if err := database.DB.Delete(&models.UserQuota{}, "user_id = ?", userID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	usage, err := storageUsage(database.DB, userID)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordDelete, usage)
+}
+
+// @Summary Définir le quota d'un rôle
+// @Description Quota en octets appliqué aux utilisateurs de ce rôle sans quota propre. Réservé aux admins
+// @Tags Quota
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		role			path		string					true		"Rôle"
+// @Param		quota			body		response.QuotaRequest	true		"Quota en octets"
+// @Success		200				{object}	models.RoleQuota
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Router /api/admin/quotas/roles/{role} [put]
+func SetRoleQuota(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	var req response.QuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	quota := models.RoleQuota{Role: c.Param("role"), QuotaBytes: *req.QuotaBytes}
+	if err := database.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&quota).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, quota)
+}
+
+// @Summary Supprimer le quota d'un rôle
+// @Description Les utilisateurs du rôle reviennent au quota par défaut. Réservé aux admins
+// @Tags Quota
+// @Security BearerAuth
+// @Produce json
+// @Param		role			path		string			true		"Rôle"
+// @Success		200				{object}	utils.AppSuccessCRUD
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Quota introuvable"
+// @Router /api/admin/quotas/roles/{role} [delete]
+func DeleteRoleQuota(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	res := database.DB.Delete(&models.RoleQuota{}, "role = ?", c.Param("role"))
+	if res.Error != nil {
+		utils.JSONAppError(c, utils.ErrInternal, res.Error)
+		return
+	}
+	if res.RowsAffected == 0 {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, nil)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordDelete, nil)
+}
+
+// Utilisateur du paramètre user_id ; l'erreur est déjà envoyée si ok est faux
+func quotaUser(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return uuid.Nil, false
+	}
+	if err := database.DB.Select("id").First(&models.User{}, "id = ?", userID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// Quota applicable : celui de l'utilisateur, sinon celui de son rôle, sinon STORAGE_QUOTA_DEFAULT.
+// limited est faux quand aucun quota ne s'applique
+func quotaFor(db *gorm.DB, userID uuid.UUID) (quota int64, source string, limited bool, err error) {
+	var userQuota models.UserQuota
+	err = db.Where("user_id = ?", userID).Limit(1).Find(&userQuota).Error
+	if err != nil || userQuota.UserID != uuid.Nil {
+		return userQuota.QuotaBytes, "user", true, err
+	}
+
+	var roleQuota models.RoleQuota
+	err = db.Model(&models.RoleQuota{}).
+		Joins("JOIN users ON users.role = role_quota.role").
+		Where("users.id = ?", userID).Limit(1).Find(&roleQuota).Error
+	if err != nil || roleQuota.Role != "" {
+		return roleQuota.QuotaBytes, "role", true, err
+	}
+
+	if defaultQuota > 0 {
+		return defaultQuota, "default", true, nil
+	}
+	return 0, "none", false, nil
+}
+
+// Espace utilisé par l'utilisateur (taille de ses fichiers et de leurs anciennes versions) et nombre de fichiers
+func storageUsed(db *gorm.DB, userID uuid.UUID) (used int64, count int64, err error) {
+	var row struct {
+		Used  int64
+		Count int64
+	}
+	err = db.Model(&models.File{}).Select("COALESCE(SUM(size), 0) AS used, COUNT(*) AS count").
+		Where("user_id = ?", userID).Scan(&row).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var versions int64
+	err = db.Model(&models.FileVersion{}).Select("COALESCE(SUM(file_versions.size), 0)").
+		Joins("JOIN files ON files.id = file_versions.file_id AND files.deleted_at IS NULL").
+		Where("files.user_id = ? AND file_versions.version <> files.version", userID).Scan(&versions).Error
+	return row.Used + versions, row.Count, err
+}
+
+func storageUsage(db *gorm.DB, userID uuid.UUID) (response.StorageUsage, error) {
+	usage := response.StorageUsage{UserID: userID}
+	var err error
+	if usage.UsedBytes, usage.FileCount, err = storageUsed(db, userID); err != nil {
+		return usage, err
+	}
+	quota, source, limited, err := quotaFor(db, userID)
+	if err != nil {
+		return usage, err
+	}
+	usage.QuotaSource = source
+	if limited {
+		remaining := max(quota-usage.UsedBytes, 0)
+		usage.QuotaBytes, usage.RemainingBytes = &quota, &remaining
+	}
+	return usage, nil
+}
+
+// checkQuota vérifie que extra octets de plus restent dans le quota de l'utilisateur.
+// Dans une transaction, lock verrouille la ligne de l'utilisateur jusqu'à la fin de
+// celle-ci : deux uploads simultanés ne peuvent pas dépasser le quota ensemble
+func checkQuota(db *gorm.DB, userID uuid.UUID, extra int64, lock bool) error {
+	if lock {
+		err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, "id = ?", userID).Error
+		if err != nil {
+			return err
+		}
+	}
+	quota, _, limited, err := quotaFor(db, userID)
+	if err != nil || !limited {
+		return err
+	}
+	used, _, err := storageUsed(db, userID)
+	if err != nil {
+		return err
+	}
+	if used+extra > quota {
+		return errQuotaExceeded
+	}
+	return nil
+}
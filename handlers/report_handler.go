@@ -0,0 +1,553 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"projet1/database"
+	"projet1/filters"
+	"projet1/jobs"
+	"projet1/models"
+	"projet1/reports"
+	"projet1/response"
+	"projet1/storage"
+	"projet1/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Génération des rapports en arrière-plan
+const reportJob = "report"
+
+var (
+	// Au-delà de ce nombre de lignes (REPORT_SYNC_MAX_ROWS), le rapport est généré en arrière-plan
+	ReportSyncMaxRows = 5000
+	// Durée de conservation des rapports générés en arrière-plan (REPORT_TTL)
+	ReportTTL = 24 * time.Hour
+)
+
+// Demande de rapport validée : type, format et requête sur les lignes
+type reportRequest struct {
+	kind   string
+	format string
+	query  *filters.Query
+	params url.Values //filter et sort, enregistrés pour la génération en arrière-plan
+	user   uuid.UUID  //Demandeur : le rapport des tâches se limite à celles qu'il peut lire
+	admin  bool
+}
+
+// Titre et constructeur de chaque type de rapport
+var reportKinds = map[string]struct {
+	title string
+	spec  filters.Spec
+	build func(ctx context.Context, req reportRequest) ([]reports.Table, error)
+}{
+	"overview": {"Résumé d'activité des utilisateurs", filters.ActivitySpec, overviewReport},
+	"tasks":    {"Liste des tâches", filters.TaskSpec, tasksReport},
+	"stats":    {"Statistiques de complétion", filters.AnonymousActivitySpec, statsReport},
+}
+
+// LoadReports lit REPORT_SYNC_MAX_ROWS (nombre de lignes) et REPORT_TTL (durée Go, ex. 24h)
+func LoadReports() error {
+	if value := strings.TrimSpace(os.Getenv("REPORT_SYNC_MAX_ROWS")); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("REPORT_SYNC_MAX_ROWS %q invalide", value)
+		}
+		ReportSyncMaxRows = n
+	}
+	if value := strings.TrimSpace(os.Getenv("REPORT_TTL")); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("REPORT_TTL %q invalide", value)
+		}
+		ReportTTL = d
+	}
+	return nil
+}
+
+// @Summary Télécharger un rapport
+// @Description Résumé d'activité des utilisateurs qui acceptent les classements (overview), liste des tâches lisibles par le demandeur (tasks) ou statistiques de complétion globales et par équipe et rôle (stats, groupes d'au moins 3 membres), en CSV, XLSX ou PDF selon le paramètre format ou l'en-tête Accept (CSV par défaut). Au-delà de REPORT_SYNC_MAX_ROWS lignes, ou avec async=true, le rapport est généré en arrière-plan : 202 avec l'état du rapport (Location), à suivre sur /api/reports/jobs/{id} jusqu'à son lien de téléchargement
+// @Tags Rapport
+// @Security BearerAuth
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf,json
+// @Param		kind		path		string		true		"overview, tasks ou stats"
+// @Param		format		query		string		false		"csv, xlsx ou pdf (prioritaire sur Accept)"
+// @Param		filter		query		string		false		"Filtre des lignes (champs du résumé d'activité pour overview, sans id ni nom pour stats, des tâches pour tasks)"
+// @Param		sort		query		string		false		"Tri des lignes (overview et tasks)"
+// @Param		async		query		bool		false		"Toujours générer en arrière-plan"
+// @Success		200			{file}		string						"Rapport"
+// @Success		202			{object}	response.ReportJob			"Rapport en cours de génération"
+// @Failure		400			{object}	utils.AppError 				"Filtre ou tri invalide"
+// @Failure		404			{object}	utils.AppError 				"Type de rapport inconnu"
+// @Failure		406			{object}	utils.AppError 				"Format non supporté"
+// @Failure		500			{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/reports/{kind} [get]
+func GetReport(c *gin.Context) {
+	format, err := reports.Negotiate(c.Query("format"), c.GetHeader("Accept"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrNotAcceptable, err)
+		return
+	}
+	params := url.Values{}
+	for _, name := range []string{"filter", "sort"} {
+		if value := c.Query(name); value != "" {
+			params.Set(name, value)
+		}
+	}
+	req, err := parseReportRequest(c.Param("kind"), format, params, utils.CurrentUserID(c))
+	if errors.Is(err, errUnknownReport) {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidFilter, err)
+		return
+	}
+
+	async, _ := strconv.ParseBool(c.Query("async"))
+	if !async {
+		rows, err := countReportRows(c.Request.Context(), req)
+		if err != nil {
+			utils.JSONAppError(c, utils.ErrInternal, err)
+			return
+		}
+		async = rows > int64(ReportSyncMaxRows)
+	}
+	if async {
+		queueReport(c, req)
+		return
+	}
+
+	report, err := buildReport(c.Request.Context(), req)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	c.Header("Content-Type", reports.ContentTypes[format])
+	c.Header("Content-Disposition", utils.ContentDisposition("attachment", reportFileName(req, report.GeneratedAt)))
+	c.Status(http.StatusOK)
+	//Les en-têtes sont partis : une erreur d'écriture (client déconnecté) est seulement journalisée
+	if err := reports.Render(c.Writer, format, report); err != nil {
+		log.Println("Erreur lors de l'écriture du rapport:", err)
+	}
+}
+
+// @Summary Mes rapports
+// @Description Rapports générés en arrière-plan pour l'utilisateur connecté et pas encore expirés, les plus récents d'abord
+// @Tags Rapport
+// @Security BearerAuth
+// @Produce json
+// @Success		200			{array}		response.ReportJob
+// @Failure		500			{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/reports/jobs [get]
+func GetReportJobs(c *gin.Context) {
+	var list []models.Report
+	err := database.DB.Where("user_id = ? AND expires_at > ?", utils.CurrentUserID(c), time.Now()).Order("created_at DESC").Find(&list).Error
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	jobs := make([]response.ReportJob, len(list))
+	for i, report := range list {
+		jobs[i] = reportJobResponse(report)
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, jobs)
+}
+
+// @Summary Etat d'un rapport
+// @Description Etat d'un rapport généré en arrière-plan (pending, ready ou failed) et son lien de téléchargement quand il est prêt. Demandeur ou admin
+// @Tags Rapport
+// @Security BearerAuth
+// @Produce json
+// @Param		id			path		string		true		"ID du rapport"
+// @Success		200			{object}	response.ReportJob
+// @Failure		403			{object}	utils.AppError 				"Accès refusé"
+// @Failure		404			{object}	utils.AppError 				"Rapport introuvable"
+// @Router /api/reports/jobs/{id} [get]
+func GetReportJob(c *gin.Context) {
+	report, ok := loadReport(c)
+	if !ok {
+		return
+	}
+	if report.Status == models.ProcessingPending {
+		c.Header("Retry-After", "5")
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, reportJobResponse(report))
+}
+
+// @Summary Télécharger un rapport généré
+// @Description Fichier d'un rapport généré en arrière-plan. Demandeur ou admin
+// @Tags Rapport
+// @Security BearerAuth
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param		id			path		string		true		"ID du rapport"
+// @Success		200			{file}		string						"Rapport"
+// @Failure		403			{object}	utils.AppError 				"Accès refusé"
+// @Failure		404			{object}	utils.AppError 				"Rapport introuvable"
+// @Failure		409			{object}	utils.AppError 				"Rapport pas encore prêt ou en échec"
+// @Failure		410			{object}	utils.AppError 				"Rapport expiré"
+// @Router /api/reports/jobs/{id}/download [get]
+func DownloadReport(c *gin.Context) {
+	report, ok := loadReport(c)
+	if !ok {
+		return
+	}
+	if report.Status != models.ProcessingReady {
+		utils.JSONAppError(c, utils.ErrReportNotReady, errors.New(report.Error))
+		return
+	}
+	if time.Now().After(report.ExpiresAt) {
+		utils.JSONAppError(c, utils.ErrReportExpired, nil)
+		return
+	}
+
+	backend, err := storage.Get(report.StorageBackend)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	obj, err := backend.Get(c.Request.Context(), report.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		utils.JSONAppError(c, utils.ErrReportExpired, err)
+		return
+	}
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	defer obj.Close()
+
+	c.DataFromReader(http.StatusOK, report.Size, reports.ContentTypes[report.Format], obj, map[string]string{
+		"Content-Disposition": utils.ContentDisposition("attachment", report.FileName),
+	})
+}
+
+var (
+	errUnknownReport = errors.New("type de rapport inconnu : overview, tasks ou stats")
+	errReportFailed  = errors.New("la génération du rapport a échoué")
+)
+
+// Valide le type de rapport et ses paramètres (filter, sort) ; @me désigne le demandeur
+func parseReportRequest(kind, format string, params url.Values, me uuid.UUID) (reportRequest, error) {
+	k, ok := reportKinds[kind]
+	if !ok {
+		return reportRequest{}, errUnknownReport
+	}
+	sort := params.Get("sort")
+	if kind == "stats" {
+		sort = ""
+	}
+	q, err := filters.ParseAs(k.spec, params.Get("filter"), sort, me)
+	if err != nil {
+		return reportRequest{}, err
+	}
+	return reportRequest{kind: kind, format: format, query: q, params: params, user: me, admin: isAdmin(me)}, nil
+}
+
+func buildReport(ctx context.Context, req reportRequest) (reports.Report, error) {
+	k := reportKinds[req.kind]
+	tables, err := k.build(ctx, req)
+	if err != nil {
+		return reports.Report{}, err
+	}
+	return reports.Report{Title: k.title, GeneratedAt: time.Now(), Tables: tables}, nil
+}
+
+// Nombre de lignes du rapport, pour choisir entre réponse directe et arrière-plan
+func countReportRows(ctx context.Context, req reportRequest) (int64, error) {
+	db := database.DB.WithContext(ctx)
+	var query *gorm.DB
+	switch req.kind {
+	case "overview":
+		query = overviewReportQuery(db, req.query)
+	case "tasks":
+		query = tasksReportQuery(db, req)
+	default:
+		return 0, nil //Quelques lignes par équipe et par rôle
+	}
+	var n int64
+	err := db.Table("(?) AS report", query).Count(&n).Error
+	return n, err
+}
+
+func reportFileName(req reportRequest, at time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", req.kind, at.Format("20060102-1504"), req.format)
+}
+
+// Résumé d'activité, comme /api/users/user_overview (sans les utilisateurs qui refusent les classements) mais sans pagination
+func overviewReportQuery(db *gorm.DB, q *filters.Query) *gorm.DB {
+	return ActivityQuery(db, q).Scopes(excludeOptedOut)
+}
+
+func overviewReport(ctx context.Context, req reportRequest) ([]reports.Table, error) {
+	var resumes []response.UserResume
+	if err := overviewReportQuery(database.DB.WithContext(ctx), req.query).Scan(&resumes).Error; err != nil {
+		return nil, err
+	}
+	table := reports.Table{
+		Title:   "Utilisateurs",
+		Columns: []string{"ID", "Nom", "Tâches", "Tâches complétées", "Taux de complétion (%)"},
+		Rows:    make([][]any, len(resumes)),
+	}
+	for i, r := range resumes {
+		table.Rows[i] = []any{r.ID, r.Nom, r.TotalTasks, r.CompletedTasks, r.CompletedPercent}
+	}
+	return []reports.Table{table}, nil
+}
+
+// Tâches lisibles par le demandeur (les siennes et celles partagées avec lui, toutes pour un admin)
+// avec le nom de leur utilisateur et de leur projet, les plus récentes d'abord par défaut
+func tasksReportQuery(db *gorm.DB, req reportRequest) *gorm.DB {
+	query := db.Model(&models.Task{}).
+		Select("tasks.*, TRIM(COALESCE(users.prenom, '') || ' ' || COALESCE(users.nom, '')) AS user_name, projects.name AS project_name").
+		Joins("LEFT JOIN users ON users.id = tasks.user_id").
+		Joins("LEFT JOIN projects ON projects.id = tasks.project_id").
+		Scopes(visibleTasks(req.user, req.admin))
+	query = req.query.Scope(query)
+	if !req.query.HasSort() {
+		query = query.Order("tasks.created_at DESC")
+	}
+	return query.Order("tasks.id")
+}
+
+func tasksReport(ctx context.Context, req reportRequest) ([]reports.Table, error) {
+	var tasks []struct {
+		models.Task
+		UserName    string
+		ProjectName string
+	}
+	if err := tasksReportQuery(database.DB.WithContext(ctx), req).Scan(&tasks).Error; err != nil {
+		return nil, err
+	}
+	table := reports.Table{
+		Title:   "Tâches",
+		Columns: []string{"ID", "Titre", "Description", "Complétée", "Créée le", "Complétée le", "Echéance", "Utilisateur", "Projet"},
+		Rows:    make([][]any, len(tasks)),
+	}
+	for i, t := range tasks {
+		table.Rows[i] = []any{t.ID, t.Title, t.Description, t.Completed, t.CreatedAT, t.CompletedAt, t.DueDate, t.UserName, t.ProjectName}
+	}
+	return []reports.Table{table}, nil
+}
+
+// Statistiques globales puis moyennes par équipe et par rôle des utilisateurs filtrés
+// (mêmes règles d'anonymat que /api/analytics/averages)
+func statsReport(ctx context.Context, req reportRequest) ([]reports.Table, error) {
+	stats, err := computeGlobalStats(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	tables := []reports.Table{{
+		Title:   "Statistiques globales",
+		Columns: []string{"Indicateur", "Valeur"},
+		Rows: [][]any{
+			{"Utilisateurs", stats.TotalUser},
+			{"Tâches", stats.TotalTasks},
+			{"Tâches complétées", stats.TotalCompleted},
+			{"Taux de complétion (%)", stats.Rate},
+		},
+	}}
+
+	for _, group := range []struct{ by, title, column string }{
+		{"team", "Par équipe", "Equipe"},
+		{"role", "Par rôle", "Rôle"},
+	} {
+		averages, err := groupAverages(database.DB.WithContext(ctx), group.by, req.query)
+		if err != nil {
+			return nil, err
+		}
+		table := reports.Table{
+			Title:   group.title,
+			Columns: []string{group.column, "Membres", "Tâches (moyenne)", "Complétées (moyenne)", "Taux de complétion moyen (%)", "Série en cours (moyenne)"},
+			Rows:    make([][]any, len(averages)),
+		}
+		for i, a := range averages {
+			table.Rows[i] = []any{a.Key, a.Members, a.AvgTotalTasks, a.AvgCompletedTasks, a.AvgCompletionRate, a.AvgCurrentStreak}
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// Enregistre la demande et lance la génération en arrière-plan (reprise au démarrage si la file est pleine)
+func queueReport(c *gin.Context, req reportRequest) {
+	report := models.Report{
+		ID:        uuid.New(),
+		UserID:    utils.CurrentUserID(c),
+		Kind:      req.kind,
+		Format:    req.format,
+		Query:     req.params.Encode(),
+		Status:    models.ProcessingPending,
+		ExpiresAt: time.Now().Add(ReportTTL),
+	}
+	if err := database.DB.Create(&report).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	jobs.Enqueue(reportJob, report.ID)
+
+	c.Header("Location", "/api/reports/jobs/"+report.ID.String())
+	c.Header("Retry-After", "5")
+	utils.JSONAppSuccessCRUD(c, utils.SuccessJobStarted, reportJobResponse(report))
+}
+
+func reportJobResponse(report models.Report) response.ReportJob {
+	job := response.ReportJob{Report: report}
+	if report.Status == models.ProcessingReady {
+		job.DownloadURL = "/api/reports/jobs/" + report.ID.String() + "/download"
+	}
+	return job
+}
+
+// Rapport du paramètre id, s'il appartient à l'utilisateur connecté (ou s'il est admin) ; l'erreur est déjà envoyée si ok est faux
+func loadReport(c *gin.Context) (models.Report, bool) {
+	var report models.Report
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return report, false
+	}
+	if err := database.DB.First(&report, "id = ?", id).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return report, false
+	}
+	if me := utils.CurrentUserID(c); report.UserID != me && !isAdmin(me) {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return report, false
+	}
+	return report, true
+}
+
+// StartReportJobs enregistre la génération des rapports et relance celle des rapports en attente
+func StartReportJobs(ctx context.Context) {
+	jobs.Register(reportJob, generateReport)
+
+	go func() {
+		var ids []uuid.UUID
+		if err := database.DB.Model(&models.Report{}).Where("status = ?", models.ProcessingPending).Pluck("id", &ids).Error; err != nil {
+			log.Println("Erreur lors de la reprise des rapports:", err)
+			return
+		}
+		for _, id := range ids {
+			if err := jobs.EnqueueWait(ctx, reportJob, id); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// generateReport produit le fichier d'un rapport en attente et l'enregistre dans le stockage par défaut
+func generateReport(ctx context.Context, id uuid.UUID) error {
+	var report models.Report
+	if err := database.DB.First(&report, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if report.Status != models.ProcessingPending {
+		return nil
+	}
+
+	//Le détail de l'erreur (requêtes, stockage) reste dans le journal : le demandeur ne voit qu'un message générique
+	err := writeReport(ctx, &report)
+	if err != nil {
+		log.Printf("Erreur lors de la génération du rapport %s: %v", report.ID, err)
+		report.Status, report.Error = models.ProcessingFailed, errReportFailed.Error()
+	}
+	now := time.Now()
+	report.CompletedAt = &now
+	report.ExpiresAt = now.Add(ReportTTL)
+	if uerr := database.DB.Save(&report).Error; uerr != nil {
+		return uerr
+	}
+	return err
+}
+
+// Génère le rapport dans un fichier temporaire (taille connue pour le stockage) puis l'envoie au stockage
+func writeReport(ctx context.Context, report *models.Report) error {
+	params, err := url.ParseQuery(report.Query)
+	if err != nil {
+		return err
+	}
+	req, err := parseReportRequest(report.Kind, report.Format, params, report.UserID)
+	if err != nil {
+		return err
+	}
+	built, err := buildReport(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "report-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if err := reports.Render(tmp, report.Format, built); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, 1)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		return err
+	}
+
+	key := "reports/" + report.ID.String() + "." + report.Format
+	if err := storage.Default.Put(ctx, key, tmp, size, reports.ContentTypes[report.Format]); err != nil {
+		return err
+	}
+	report.Status = models.ProcessingReady
+	report.FileName = reportFileName(req, built.GeneratedAt)
+	report.Rows = built.Rows()
+	report.Size = size
+	report.StorageBackend = storage.Default.Name()
+	report.StorageKey = key
+	return nil
+}
+
+// StartReportJanitor supprime régulièrement les rapports expirés et leur fichier
+func StartReportJanitor(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			n, err := purgeExpiredReports(context.Background())
+			if err != nil {
+				log.Println("Erreur lors de la suppression des rapports expirés:", err)
+			} else if n > 0 {
+				log.Printf("%d rapport(s) expiré(s) supprimé(s)", n)
+			}
+		}
+	}()
+}
+
+func purgeExpiredReports(ctx context.Context) (int, error) {
+	var expired []models.Report
+	if err := database.DB.Where("expires_at < ?", time.Now()).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+	for _, report := range expired {
+		if report.StorageKey != "" {
+			backend, err := storage.Get(report.StorageBackend)
+			if err != nil {
+				return 0, err
+			}
+			if err := backend.Delete(ctx, report.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return 0, err
+			}
+		}
+		if err := database.DB.Unscoped().Delete(&report).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(expired), nil
+}
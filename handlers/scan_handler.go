@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"projet1/database"
+	"projet1/jobs"
+	"projet1/models"
+	"projet1/scan"
+	"projet1/storage"
+	"projet1/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// @Summary Fichiers en quarantaine
+// @Description Versions de fichiers bloquées par l'analyse antivirus : menace détectée (infected, par défaut), analyse échouée (failed) ou en attente (pending). Réservé aux admins
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param		status			query		string			false		"infected (par défaut), failed ou pending"
+// @Success		200				{array}		models.FileVersion
+// @Failure		400				{object}	utils.AppError 				"Statut invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Router /api/admin/quarantine [get]
+func GetQuarantinedFiles(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	status := c.DefaultQuery("status", models.ScanInfected)
+	if status != models.ScanInfected && status != models.ScanFailed && status != models.ScanPending {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("statut inconnu"))
+		return
+	}
+
+	versions := []models.FileVersion{}
+	err := database.DB.Joins("JOIN files ON files.id = file_versions.file_id AND files.deleted_at IS NULL").
+		Where("file_versions.scan_status = ?", status).
+		Select("file_versions.*").
+		Order("file_versions.created_at DESC").Find(&versions).Error
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, versions)
+}
+
+// @Summary Analyser à nouveau un fichier
+// @Description Remet toutes les versions du fichier en quarantaine et relance leur analyse antivirus (après une mise à jour des signatures ou un faux positif). Réservé aux admins
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Success		202				{object}	utils.AppSuccessCRUD
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable"
+// @Router /api/admin/quarantine/{file_id}/rescan [post]
+func RescanFile(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	file, ok := loadAccessibleFile(c, accessRead)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.FileVersion{}).Where("file_id = ?", file.ID).Update("scan_status", models.ScanPending).Error; err != nil {
+			return err
+		}
+		return tx.Model(&file).Update("scan_status", models.ScanPending).Error
+	})
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	jobs.Enqueue(scanJob, file.ID)
+
+	utils.JSONAppSuccessCRUD(c, utils.SuccessJobStarted, nil)
+}
+
+// scanFile analyse les versions du fichier encore en quarantaine. Le verdict est enregistré
+// sur chaque version et, pour la version courante, sur le fichier ; un contenu sain
+// déclenche ensuite l'aperçu et l'extraction du texte
+func scanFile(ctx context.Context, fileID uuid.UUID) error {
+	var versions []models.FileVersion
+	err := database.DB.Where("file_id = ? AND scan_status IN ?", fileID, []string{models.ScanPending, models.ScanFailed}).
+		Find(&versions).Error
+	if err != nil {
+		return err
+	}
+
+	var failed error
+	for _, v := range versions {
+		result, err := scanObject(ctx, v.StorageBackend, v.StorageKey)
+		now := time.Now()
+		changes := map[string]interface{}{"scan_status": models.ScanClean, "scan_signature": "", "scanned_at": &now}
+		switch {
+		case err != nil:
+			log.Printf("fichier %s v%d: analyse antivirus impossible: %v", v.FileID, v.Version, err)
+			changes["scan_status"] = models.ScanFailed
+			failed = err
+		case result.Infected:
+			log.Printf("fichier %s v%d: menace détectée (%s), fichier bloqué", v.FileID, v.Version, result.Signature)
+			changes["scan_status"], changes["scan_signature"] = models.ScanInfected, result.Signature
+		}
+
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.FileVersion{}).Where("id = ?", v.ID).Updates(changes).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.File{}).Where("id = ? AND version = ?", v.FileID, v.Version).Updates(changes).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var file models.File
+	if err := database.DB.First(&file, "id = ?", fileID).Error; err != nil {
+		return err
+	}
+	if !file.Quarantined() {
+		queueFileJobs(file)
+	}
+	return failed
+}
+
+// Analyse un objet stocké avec le scanner configuré
+func scanObject(ctx context.Context, backendName, key string) (scan.Result, error) {
+	backend, err := storage.Get(backendName)
+	if err != nil {
+		return scan.Result{}, err
+	}
+	obj, err := backend.Get(ctx, key)
+	if err != nil {
+		return scan.Result{}, err
+	}
+	defer obj.Close()
+	return scan.Current.Scan(ctx, obj)
+}
+
+// Code d'erreur d'un fichier en quarantaine
+func quarantineAppError(file models.File) utils.AppError {
+	if file.ScanStatus == models.ScanInfected {
+		return utils.ErrFileInfected
+	}
+	return utils.ErrFileQuarantined
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"html"
+	"projet1/database"
+	"projet1/filters"
+	"projet1/models"
+	"projet1/pagination"
+	"projet1/response"
+	"projet1/utils"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Partie de la requête UNION pour chaque type d'entité.
+// {vec} est remplacé par la colonne tsvector de la langue choisie et {cfg} par sa configuration
+var searchQueries = map[string]string{
+	"task": `SELECT 'task' AS type, t.id, t.title AS title,
+		ts_headline('{cfg}', coalesce(t.title, '') || ' - ' || coalesce(t.description, ''), q.query, '` + headlineOptions + `') AS snippet,
+		ts_rank(t.{vec}, q.query) + similarity(t.title, q.raw) AS rank
+		FROM tasks t, q
+		WHERE t.deleted_at IS NULL AND (t.{vec} @@ q.query OR q.raw <% t.title)`,
+	"user": `SELECT 'user' AS type, u.id, coalesce(u.nom, '') || ' ' || coalesce(u.prenom, '') AS title,
+		ts_headline('{cfg}', coalesce(u.nom, '') || ' ' || coalesce(u.prenom, '') || ' - ' || coalesce(u.email, ''), q.query, '` + headlineOptions + `') AS snippet,
+		ts_rank(u.{vec}, q.query) + similarity(u.nom, q.raw) AS rank
+		FROM users u, q
+		WHERE u.deleted_at IS NULL AND (u.{vec} @@ q.query OR q.raw <% u.nom)`,
+	"file": `SELECT 'file' AS type, f.id, f.file_name AS title,
+		ts_headline('{cfg}', coalesce(f.file_name, ''), q.query, '` + headlineOptions + `') AS snippet,
+		ts_rank(f.{vec}, q.query) + similarity(f.file_name, q.raw) AS rank
+		FROM files f, q
+		WHERE f.deleted_at IS NULL AND (f.{vec} @@ q.query OR q.raw <% f.file_name)`,
+}
+
+// Restriction ajoutée à chaque partie de l'UNION pour un utilisateur non admin : ses tâches et
+// fichiers et ceux partagés avec lui (les deux ? valent l'utilisateur connecté)
+var searchAccess = map[string]string{
+	"task": ` AND (t.user_id = ? OR t.id IN (SELECT resource_id FROM shares WHERE resource_type = '` + models.ResourceTask + `' AND user_id = ?))`,
+	"file": ` AND (f.user_id = ? OR f.id IN (SELECT resource_id FROM shares WHERE resource_type = '` + models.ResourceFile + `' AND user_id = ?))`,
+}
+
+// Délimiteurs des termes trouvés dans les extraits de ts_headline : des caractères de contrôle,
+// remplacés par <mark></mark> une fois le reste de l'extrait échappé (voir highlightSnippet)
+const (
+	headlineStart = "\x02"
+	headlineStop  = "\x03"
+)
+
+const headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", MaxWords=20, MinWords=5, MaxFragments=2"
+
+var headlineMarks = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// Extrait de ts_headline prêt à afficher : le texte (titres, descriptions, contenu des documents)
+// est échappé en HTML et seuls les délimiteurs deviennent des balises <mark>
+func highlightSnippet(snippet string) string {
+	return headlineMarks.Replace(html.EscapeString(snippet))
+}
+
+// @Summary 		Recherche plein texte
+// @Description 	Recherche dans les tâches, les utilisateurs et les fichiers, classée par pertinence. Les mots sont recherchés par préfixe et les fautes de frappe sont tolérées (trigrammes). Hors admin, seules les tâches et fichiers de l'utilisateur ou partagés avec lui sont renvoyés. L'extrait est du HTML échappé où seuls les termes trouvés sont entre <mark></mark>
+// @Tags			Recherche
+// @Security		BearerAuth
+// @Produce			json
+// @Param			q			query			string			true			"Les termes recherchés"
+// @Param			lang		query			string			false			"La langue de la recherche : fr (par défaut) ou en"
+// @Param			types		query			string			false			"Les types séparés par des virgules : task,user,file (tous par défaut)"
+// @Param			page		query			string			false			"le numero du page"
+// @Param			limit		query			string			false			"la limite des elements (max 50)"
+// @Success			200			{array}			response.SearchResult
+// @Failure			400			{object}		utils.AppError 							"Requête invalide"
+// @Failure			500			{object}		utils.AppError 							"Erreur Interne su serveur"
+// @Router			/api/search  [get]
+func Search(c *gin.Context) {
+	raw := strings.TrimSpace(c.Query("q"))
+	tsQuery := prefixTsQuery(raw)
+	if tsQuery == "" {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("le paramètre q est obligatoire"))
+		return
+	}
+
+	lang := c.DefaultQuery("lang", "fr")
+	cfg, ok := database.SearchConfigs[lang]
+	if !ok {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("langue non supportée"))
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	me := utils.CurrentUserID(c)
+	admin := isAdmin(me)
+
+	//Construction de l'UNION avec les types demandés, chacun une seule fois
+	var parts []string
+	args := []any{tsQuery, raw}
+	seen := map[string]bool{}
+	for _, t := range strings.Split(c.DefaultQuery("types", "task,user,file"), ",") {
+		t = strings.TrimSpace(t)
+		part, ok := searchQueries[t]
+		if !ok {
+			utils.JSONAppError(c, utils.ErrBadRequest, errors.New("type de recherche inconnu"))
+			return
+		}
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if access, ok := searchAccess[t]; ok && !admin {
+			part += access
+			args = append(args, me, me)
+		}
+		part = strings.ReplaceAll(part, "{vec}", "search_"+lang)
+		parts = append(parts, strings.ReplaceAll(part, "{cfg}", cfg))
+	}
+
+	sql := "WITH q AS (SELECT to_tsquery('" + cfg + "', ?) AS query, ?::text AS raw) " +
+		strings.Join(parts, " UNION ALL ") +
+		" ORDER BY rank DESC LIMIT ? OFFSET ?"
+
+	results := []response.SearchResult{}
+	if err := database.DB.Raw(sql, append(args, limit, (page-1)*limit)...).Scan(&results).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Snippet)
+	}
+
+	utils.JSONAppSuccess(c, "Résultats de la recherche", results)
+}
+
+// @Summary 		Rechercher des fichiers
+// @Description 	Recherche dans le nom des fichiers et dans le texte extrait des PDF et DOCX (version courante). Mêmes paramètres de pagination et de filtre que paginated_files ; en mode offset, les résultats sont classés par pertinence sauf si sort est fourni. Un utilisateur voit ses fichiers et ceux partagés avec lui, un admin tous les fichiers. L'extrait est du HTML échappé
+// @Tags			Recherche
+// @Security		BearerAuth
+// @Produce			json
+// @Param			q				query			string				true				"Les termes recherchés"
+// @Param			lang			query			string				false				"La langue de la recherche : fr (par défaut) ou en"
+// @Param			page		 	query 			string 				false 				"le numero du page (mode offset)"
+// @Param			cursor			query			string				false				"Curseur opaque (mode keyset, vide pour la première page)"
+// @Param			limit			query			string				false				"la limite des elements (max 100)"
+// @Param			total			query			bool				false				"Inclure le nombre total"
+// @Param			filter			query			string				false				"Filtre (ex. file_type:in:.pdf|.docx)"
+// @Param			sort			query			string				false				"Tri (ex. -created_at,file_name), mode offset uniquement"
+// @Success			200				{object}		pagination.Envelope{items=[]response.FileSearchResult}
+// @Failure			400				{object}		utils.AppError 							"Requête invalide"
+// @Failure			500				{object}		utils.AppError 							"Erreur Interne su serveur"
+// @Router			/api/files/search  [get]
+func SearchFiles(c *gin.Context) {
+	raw := strings.TrimSpace(c.Query("q"))
+	tsQuery := prefixTsQuery(raw)
+	if tsQuery == "" {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("le paramètre q est obligatoire"))
+		return
+	}
+	lang := c.DefaultQuery("lang", "fr")
+	cfg, ok := database.SearchConfigs[lang]
+	if !ok {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("langue non supportée"))
+		return
+	}
+
+	p, err := pagination.FromContext(c)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidPagination, err)
+		return
+	}
+	q, err := filters.FromContext(c, filters.FileSpec)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidFilter, err)
+		return
+	}
+	if p.Keyset && q.HasSort() {
+		utils.JSONAppError(c, utils.ErrInvalidPagination, &pagination.ParamError{Param: "sort", Reason: "non disponible avec cursor"})
+		return
+	}
+
+	me := utils.CurrentUserID(c)
+	tsq := "to_tsquery('" + cfg + "', ?)"
+	vec := "search_" + lang
+	query := database.DB.Model(&models.File{}).
+		Select("files.*, ts_headline('"+cfg+"', coalesce(file_texts.content, files.file_name), "+tsq+", '"+headlineOptions+"') AS snippet, "+
+			"ts_rank(files."+vec+", "+tsq+") + coalesce(ts_rank(file_texts."+vec+", "+tsq+"), 0) + similarity(files.file_name, ?) AS rank",
+			tsQuery, tsQuery, tsQuery, raw).
+		Joins("LEFT JOIN file_texts ON file_texts.file_id = files.id AND file_texts.version = files.version").
+		Where("files."+vec+" @@ "+tsq+" OR file_texts."+vec+" @@ "+tsq+" OR ? <% files.file_name", tsQuery, tsQuery, raw).
+		Scopes(q.Scope, visibleFiles(me, isAdmin(me)))
+	//Classement par pertinence, (created_at, id) départage ensuite
+	if !p.Keyset && !q.HasSort() {
+		query = query.Order("rank DESC")
+	}
+
+	page, err := pagination.Find[response.FileSearchResult](c, query, "files", p)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	items := page.Items.([]response.FileSearchResult)
+	for i := range items {
+		items[i].Snippet = highlightSnippet(items[i].Snippet)
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, page)
+}
+
+// Transforme la saisie en requête tsquery par préfixe : "rapp mens" => "rapp:* & mens:*".
+// Seuls les lettres et chiffres sont gardés, la saisie ne peut donc pas casser la syntaxe tsquery
+func prefixTsQuery(input string) string {
+	words := strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
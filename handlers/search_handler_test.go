@@ -0,0 +1,48 @@
+package handlers
+
+import "testing"
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{"texte simple", "rapport " + headlineStart + "mensuel" + headlineStop, "rapport <mark>mensuel</mark>"},
+		{"HTML du document échappé", "a <b>" + headlineStart + "rapport" + headlineStop + "</b> & co",
+			"a &lt;b&gt;<mark>rapport</mark>&lt;/b&gt; &amp; co"},
+		{"balise mark dans le texte", "<mark>faux</mark> " + headlineStart + "vrai" + headlineStop,
+			"&lt;mark&gt;faux&lt;/mark&gt; <mark>vrai</mark>"},
+		{"script", `<script>alert("x")</script>`, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;"},
+		{"plusieurs termes", headlineStart + "a" + headlineStop + " … " + headlineStart + "b" + headlineStop,
+			"<mark>a</mark> … <mark>b</mark>"},
+		{"vide", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(tt.snippet); got != tt.want {
+				t.Errorf("highlightSnippet(%q) = %q, attendu %q", tt.snippet, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixTsQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"rapp mens", "rapp:* & mens:*"},
+		{"  réunion   équipe ", "réunion:* & équipe:*"},
+		{"v2 2024", "v2:* & 2024:*"},
+		{"a & b | !c", "a:* & b:* & c:*"},
+		{"l'été", "l:* & été:*"},
+		{"':* <-> (", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := prefixTsQuery(tt.input); got != tt.want {
+			t.Errorf("prefixTsQuery(%q) = %q, attendu %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,618 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Ressource partageable désignée par la route : /api/files/:file_id/... ou /api/tasks/:id/...
+type shareTarget struct {
+	Type    string
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+}
+
+// Fichier ou tâche de la route, si l'utilisateur connecté y a au moins l'accès need ; l'erreur est déjà envoyée si ok est faux
+func loadShareTarget(c *gin.Context, need accessLevel) (shareTarget, bool) {
+	if c.Param("file_id") != "" {
+		file, ok := loadAccessibleFile(c, need)
+		return shareTarget{models.ResourceFile, file.ID, file.UserID}, ok
+	}
+	task, ok := loadAccessibleTask(c, need)
+	return shareTarget{models.ResourceTask, task.ID, task.UserID}, ok
+}
+
+// @Summary Partager un fichier
+// @Description Donne à un autre utilisateur l'accès en lecture (read, par défaut) ou en modification (edit) à un fichier. Un nouveau partage avec le même utilisateur remplace le précédent. Réservé au propriétaire (ou admin)
+// @Tags Partage
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		file_id			path		string					true		"File ID"
+// @Param		share			body		response.ShareRequest	true		"Destinataire et droits"
+// @Success		201				{object}	models.Share
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Ressource ou utilisateur introuvable"
+// @Router /api/files/{file_id}/shares [post]
+func ShareFile(c *gin.Context) {
+	createShare(c)
+}
+
+// @Summary Partager une tâche
+// @Description Donne à un autre utilisateur l'accès en lecture (read, par défaut) ou en modification (edit) à une tâche. Un nouveau partage avec le même utilisateur remplace le précédent. Réservé au propriétaire (ou admin)
+// @Tags Partage
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		id				path		string					true		"ID de la tâche"
+// @Param		share			body		response.ShareRequest	true		"Destinataire et droits"
+// @Success		201				{object}	models.Share
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Ressource ou utilisateur introuvable"
+// @Router /api/tasks/{id}/shares [post]
+func ShareTask(c *gin.Context) {
+	createShare(c)
+}
+
+func createShare(c *gin.Context) {
+	target, ok := loadShareTarget(c, accessOwner)
+	if !ok {
+		return
+	}
+	var req response.ShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	if req.Permission == "" {
+		req.Permission = models.PermissionRead
+	}
+	if req.UserID == target.OwnerID {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("le propriétaire a déjà accès à la ressource"))
+		return
+	}
+	var user models.User
+	if err := database.DB.Select("id").First(&user, "id = ?", req.UserID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrUserNotFound, err)
+		return
+	}
+
+	share := models.Share{
+		ResourceType: target.Type,
+		ResourceID:   target.ID,
+		UserID:       req.UserID,
+		OwnerID:      target.OwnerID,
+		Permission:   req.Permission,
+	}
+	err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "resource_type"}, {Name: "resource_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"permission", "updated_at"}),
+	}).Create(&share).Error
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordCreated, share)
+}
+
+// @Summary Partages d'un fichier
+// @Description Utilisateurs avec qui le fichier est partagé. Réservé au propriétaire (ou admin)
+// @Tags Partage
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Success		200				{array}		models.Share
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Ressource introuvable"
+// @Router /api/files/{file_id}/shares [get]
+func GetFileShares(c *gin.Context) {
+	getShares(c)
+}
+
+// @Summary Partages d'une tâche
+// @Description Utilisateurs avec qui la tâche est partagée. Réservé au propriétaire (ou admin)
+// @Tags Partage
+// @Security BearerAuth
+// @Produce json
+// @Param		id				path		string			true		"ID de la tâche"
+// @Success		200				{array}		models.Share
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Ressource introuvable"
+// @Router /api/tasks/{id}/shares [get]
+func GetTaskShares(c *gin.Context) {
+	getShares(c)
+}
+
+func getShares(c *gin.Context) {
+	target, ok := loadShareTarget(c, accessOwner)
+	if !ok {
+		return
+	}
+	var shares []models.Share
+	err := database.DB.Where("resource_type = ? AND resource_id = ?", target.Type, target.ID).
+		Order("created_at").Find(&shares).Error
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, shares)
+}
+
+// @Summary Retirer le partage d'un fichier
+// @Description Retire l'accès d'un utilisateur à un fichier. Le propriétaire (ou un admin) peut retirer n'importe quel partage, le destinataire peut retirer le sien
+// @Tags Partage
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Param		user_id			path		string			true		"Destinataire du partage"
+// @Success		200				{object}	utils.AppSuccessCRUD
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Partage introuvable"
+// @Router /api/files/{file_id}/shares/{user_id} [delete]
+func UnshareFile(c *gin.Context) {
+	deleteShare(c)
+}
+
+// @Summary Retirer le partage d'une tâche
+// @Description Retire l'accès d'un utilisateur à une tâche. Le propriétaire (ou un admin) peut retirer n'importe quel partage, le destinataire peut retirer le sien
+// @Tags Partage
+// @Security BearerAuth
+// @Produce json
+// @Param		id				path		string			true		"ID de la tâche"
+// @Param		user_id			path		string			true		"Destinataire du partage"
+// @Success		200				{object}	utils.AppSuccessCRUD
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Partage introuvable"
+// @Router /api/tasks/{id}/shares/{user_id} [delete]
+func UnshareTask(c *gin.Context) {
+	deleteShare(c)
+}
+
+func deleteShare(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	target, ok := loadShareTarget(c, accessRead)
+	if !ok {
+		return
+	}
+	me := utils.CurrentUserID(c)
+	if userID != me && resourceAccess(me, target.Type, target.ID, target.OwnerID) < accessOwner {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return
+	}
+
+	res := database.DB.Where("resource_type = ? AND resource_id = ? AND user_id = ?", target.Type, target.ID, userID).
+		Delete(&models.Share{})
+	if res.Error != nil {
+		utils.JSONAppError(c, utils.ErrInternal, res.Error)
+		return
+	}
+	if res.RowsAffected == 0 {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, nil)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordDelete, nil)
+}
+
+// @Summary Créer un lien public vers un fichier
+// @Description Lien en lecture seule vers un fichier, utilisable sans compte jusqu'à son expiration (facultative) ou sa révocation. Avec un mot de passe, il doit être envoyé dans l'en-tête X-Link-Password. Les consultations sont comptées. Réservé au propriétaire (ou admin)
+// @Tags Partage
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		file_id			path		string						true		"File ID"
+// @Param		link			body		response.PublicLinkRequest	true		"Expiration et mot de passe (facultatifs)"
+// @Success		201				{object}	response.PublicLink
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Ressource introuvable"
+// @Router /api/files/{file_id}/public_links [post]
+func CreateFilePublicLink(c *gin.Context) {
+	createPublicLink(c)
+}
+
+// @Summary Créer un lien public vers une tâche
+// @Description Lien en lecture seule vers une tâche, utilisable sans compte jusqu'à son expiration (facultative) ou sa révocation. Avec un mot de passe, il doit être envoyé dans l'en-tête X-Link-Password. Les consultations sont comptées. Réservé au propriétaire (ou admin)
+// @Tags Partage
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		id				path		string						true		"ID de la tâche"
+// @Param		link			body		response.PublicLinkRequest	true		"Expiration et mot de passe (facultatifs)"
+// @Success		201				{object}	response.PublicLink
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Ressource introuvable"
+// @Router /api/tasks/{id}/public_links [post]
+func CreateTaskPublicLink(c *gin.Context) {
+	createPublicLink(c)
+}
+
+func createPublicLink(c *gin.Context) {
+	target, ok := loadShareTarget(c, accessOwner)
+	if !ok {
+		return
+	}
+	var req response.PublicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("expires_at doit être dans le futur"))
+		return
+	}
+
+	token, err := newLinkToken()
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	link := models.PublicLink{
+		Token:        token,
+		ResourceType: target.Type,
+		ResourceID:   target.ID,
+		OwnerID:      target.OwnerID,
+		CreatedBy:    utils.CurrentUserID(c),
+		ExpiresAt:    req.ExpiresAt,
+	}
+	if req.Password != "" {
+		if link.PasswordHash, err = utils.HashPassword(c, req.Password); err != nil {
+			return
+		}
+	}
+	names, err := resourceNames([]models.Share{{ResourceType: link.ResourceType, ResourceID: link.ResourceID}})
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	if err := database.DB.Create(&link).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordCreated, publicLinkResponse(link, names))
+}
+
+// @Summary Révoquer un lien public
+// @Description Désactive un lien public avant son expiration (propriétaire de la ressource, créateur du lien ou admin)
+// @Tags Partage
+// @Security BearerAuth
+// @Produce json
+// @Param		link_id			path		string			true		"ID du lien"
+// @Success		200				{object}	utils.AppSuccessCRUD
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Lien introuvable"
+// @Router /api/public_links/{link_id} [delete]
+func RevokePublicLink(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("link_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	var link models.PublicLink
+	if err := database.DB.First(&link, "id = ?", linkID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+	me := utils.CurrentUserID(c)
+	if link.OwnerID != me && link.CreatedBy != me && !isAdmin(me) {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return
+	}
+
+	if link.RevokedAt == nil {
+		if err := database.DB.Model(&link).Update("revoked_at", time.Now()).Error; err != nil {
+			utils.JSONAppError(c, utils.ErrInternal, err)
+			return
+		}
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, link)
+}
+
+// @Summary Ce que j'ai partagé
+// @Description Partages et liens publics (y compris expirés ou révoqués, avec le nombre de consultations) des fichiers et tâches de l'utilisateur connecté, du plus récent au plus ancien
+// @Tags Partage
+// @Security BearerAuth
+// @Produce json
+// @Success		200				{object}	response.SharedByMe
+// @Failure		500				{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/shares/ [get]
+func GetMyShares(c *gin.Context) {
+	me := utils.CurrentUserID(c)
+
+	var shares []models.Share
+	if err := database.DB.Where("owner_id = ?", me).Order("created_at DESC").Find(&shares).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	var links []models.PublicLink
+	if err := database.DB.Where("owner_id = ?", me).Order("created_at DESC").Find(&links).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+
+	refs := append([]models.Share{}, shares...)
+	for _, link := range links {
+		refs = append(refs, models.Share{ResourceType: link.ResourceType, ResourceID: link.ResourceID})
+	}
+	names, err := resourceNames(refs)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+
+	result := response.SharedByMe{
+		Shares:      sharedResources(shares, names),
+		PublicLinks: make([]response.PublicLink, 0, len(links)),
+	}
+	for _, link := range links {
+		result.PublicLinks = append(result.PublicLinks, publicLinkResponse(link, names))
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, result)
+}
+
+// @Summary Partagés avec moi
+// @Description Fichiers et tâches que d'autres utilisateurs ont partagés avec l'utilisateur connecté
+// @Tags Partage
+// @Security BearerAuth
+// @Produce json
+// @Success		200				{array}		response.SharedResource
+// @Failure		500				{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/shares/received [get]
+func GetSharedWithMe(c *gin.Context) {
+	var shares []models.Share
+	if err := database.DB.Where("user_id = ?", utils.CurrentUserID(c)).Order("created_at DESC").Find(&shares).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	names, err := resourceNames(shares)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, sharedResources(shares, names))
+}
+
+// @Summary Consulter un lien public
+// @Description Fichier (informations et lien de téléchargement) ou tâche partagé par un lien public, sans token. Chaque consultation d'une tâche est comptée ; pour un fichier, seuls les téléchargements le sont. Après plusieurs mots de passe incorrects, le lien est bloqué pour une durée croissante
+// @Tags Partage
+// @Produce json
+// @Param		token				path		string			true		"Jeton du lien"
+// @Param		X-Link-Password		header		string			false		"Mot de passe du lien"
+// @Success		200					{object}	response.PublicResource
+// @Failure		401					{object}	utils.AppError 				"Mot de passe manquant ou incorrect"
+// @Failure		404					{object}	utils.AppError 				"Lien ou ressource introuvable"
+// @Failure		410					{object}	utils.AppError 				"Lien expiré ou révoqué"
+// @Failure		429					{object}	utils.AppError 				"Trop de mots de passe incorrects, réessayer après Retry-After"
+// @Router /public/{token} [get]
+func GetPublicResource(c *gin.Context) {
+	link, ok := loadPublicLink(c)
+	if !ok {
+		return
+	}
+
+	result := response.PublicResource{ResourceType: link.ResourceType, ExpiresAt: link.ExpiresAt}
+	switch link.ResourceType {
+	case models.ResourceFile:
+		var file models.File
+		if err := database.DB.First(&file, "id = ?", link.ResourceID).Error; err != nil {
+			utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+			return
+		}
+		result.File = &response.PublicFile{
+			ID:        file.ID,
+			FileName:  file.FileName,
+			MimeType:  file.MimeType,
+			Size:      file.Size,
+			Version:   file.Version,
+			UpdatedAt: file.UpdatedAt,
+		}
+		result.DownloadURL = "/public/" + link.Token + "/download"
+	case models.ResourceTask:
+		var task models.Task
+		if err := database.DB.Preload("Tags").First(&task, "id = ?", link.ResourceID).Error; err != nil {
+			utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+			return
+		}
+		result.Task = &task
+	}
+
+	//Un lien de fichier est compté au téléchargement, pas à l'ouverture de la page
+	if link.ResourceType == models.ResourceTask {
+		countLinkView(link)
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, result)
+}
+
+// @Summary Télécharger un fichier avec un lien public
+// @Description Contenu du fichier partagé par un lien public, sans token. Chaque téléchargement est compté comme une consultation, sauf les requêtes partielles (Range)
+// @Tags Partage
+// @Produce octet-stream
+// @Param		token				path		string			true		"Jeton du lien"
+// @Param		X-Link-Password		header		string			false		"Mot de passe du lien"
+// @Param		inline				query		bool			false		"Afficher dans le navigateur au lieu de télécharger"
+// @Param		Range				header		string			false		"Plage d'octets (ex. bytes=0-1023)"
+// @Success		200					{file}		string						"File Content"
+// @Success		206					{file}		string						"Partial Content"
+// @Failure		401					{object}	utils.AppError 				"Mot de passe manquant ou incorrect"
+// @Failure		403					{object}	utils.AppError 				"Fichier infecté"
+// @Failure		404					{object}	utils.AppError 				"Lien ou fichier introuvable"
+// @Failure		410					{object}	utils.AppError 				"Lien expiré ou révoqué"
+// @Failure		423					{object}	utils.AppError 				"Fichier en attente d'analyse antivirus"
+// @Failure		429					{object}	utils.AppError 				"Trop de mots de passe incorrects, réessayer après Retry-After"
+// @Router /public/{token}/download [get]
+func DownloadPublicFile(c *gin.Context) {
+	link, ok := loadPublicLink(c)
+	if !ok {
+		return
+	}
+	var file models.File
+	if link.ResourceType != models.ResourceFile {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, errors.New("ce lien ne partage pas un fichier"))
+		return
+	}
+	if err := database.DB.First(&file, "id = ?", link.ResourceID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+
+	if c.Request.Method == http.MethodGet && c.GetHeader("Range") == "" && !file.Quarantined() {
+		countLinkView(link)
+	}
+	serveStoredFile(c, file)
+}
+
+// Lien public actif du paramètre token, mot de passe vérifié ; l'erreur est déjà envoyée si ok est faux
+func loadPublicLink(c *gin.Context) (models.PublicLink, bool) {
+	var link models.PublicLink
+	if err := database.DB.First(&link, "token = ?", c.Param("token")).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return link, false
+	}
+	if !link.Active(time.Now()) {
+		utils.JSONAppError(c, utils.ErrLinkExpired, nil)
+		return link, false
+	}
+	if link.PasswordHash != "" {
+		now := time.Now()
+		if link.LockedUntil != nil && now.Before(*link.LockedUntil) {
+			c.Header("Retry-After", strconv.Itoa(int(link.LockedUntil.Sub(now).Seconds())+1))
+			utils.JSONAppError(c, utils.ErrLinkLocked, nil)
+			return link, false
+		}
+		password := c.GetHeader("X-Link-Password")
+		if password == "" {
+			utils.JSONAppError(c, utils.ErrLinkPasswordRequired, nil)
+			return link, false
+		}
+		if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+			if err := failLinkPassword(link); err != nil {
+				utils.JSONAppError(c, utils.ErrInternal, err)
+				return link, false
+			}
+			utils.JSONAppError(c, utils.ErrLinkPasswordRequired, nil)
+			return link, false
+		}
+		if link.FailedAttempts > 0 {
+			database.DB.Model(&link).UpdateColumns(map[string]any{"failed_attempts": 0, "locked_until": nil})
+		}
+	}
+	return link, true
+}
+
+// Échecs de mot de passe tolérés avant de bloquer un lien, puis durée du premier blocage,
+// doublée à chaque nouvel échec jusqu'à linkLockMax
+const (
+	linkMaxAttempts = 5
+	linkLockBase    = time.Minute
+	linkLockMax     = time.Hour
+)
+
+// Compte un mot de passe incorrect et bloque le lien au-delà de linkMaxAttempts. Le compteur est
+// en base, et non en mémoire, pour être partagé par toutes les instances
+func failLinkPassword(link models.PublicLink) error {
+	return database.DB.Model(&link).UpdateColumns(map[string]any{
+		"failed_attempts": gorm.Expr("failed_attempts + 1"),
+		"locked_until": gorm.Expr(`CASE WHEN failed_attempts + 1 >= ?
+			THEN now() + make_interval(secs => LEAST(? * power(2, LEAST(failed_attempts + 1 - ?, 30)), ?))
+			ELSE locked_until END`, linkMaxAttempts, linkLockBase.Seconds(), linkMaxAttempts, linkLockMax.Seconds()),
+	}).Error
+}
+
+// Compteur de consultations, incrémenté en base pour ne perdre aucune requête concurrente
+func countLinkView(link models.PublicLink) {
+	database.DB.Model(&link).UpdateColumns(map[string]any{
+		"views":          gorm.Expr("views + 1"),
+		"last_viewed_at": time.Now(),
+	})
+}
+
+// Jeton aléatoire de 192 bits, utilisable tel quel dans une URL
+func newLinkToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func publicLinkResponse(link models.PublicLink, names map[uuid.UUID]string) response.PublicLink {
+	return response.PublicLink{
+		PublicLink:        link,
+		URL:               "/public/" + link.Token,
+		PasswordProtected: link.PasswordHash != "",
+		ResourceName:      names[link.ResourceID],
+	}
+}
+
+func sharedResources(shares []models.Share, names map[uuid.UUID]string) []response.SharedResource {
+	result := make([]response.SharedResource, 0, len(shares))
+	for _, share := range shares {
+		result = append(result, response.SharedResource{Share: share, ResourceName: names[share.ResourceID]})
+	}
+	return result
+}
+
+// Nom des fichiers et titre des tâches référencés, en deux requêtes
+func resourceNames(refs []models.Share) (map[uuid.UUID]string, error) {
+	var fileIDs, taskIDs []uuid.UUID
+	for _, ref := range refs {
+		switch ref.ResourceType {
+		case models.ResourceFile:
+			fileIDs = append(fileIDs, ref.ResourceID)
+		case models.ResourceTask:
+			taskIDs = append(taskIDs, ref.ResourceID)
+		}
+	}
+
+	names := map[uuid.UUID]string{}
+	if len(fileIDs) > 0 {
+		var files []models.File
+		if err := database.DB.Select("id", "file_name").Where("id IN ?", fileIDs).Find(&files).Error; err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			names[file.ID] = file.FileName
+		}
+	}
+	if len(taskIDs) > 0 {
+		var tasks []models.Task
+		if err := database.DB.Select("id", "title").Where("id IN ?", taskIDs).Find(&tasks).Error; err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			names[task.ID] = task.Title
+		}
+	}
+	return names, nil
+}
+
+// Supprime les partages et les liens publics d'une ressource supprimée
+func deleteResourceShares(tx *gorm.DB, resourceType string, resourceID uuid.UUID) error {
+	if err := tx.Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).Delete(&models.Share{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).Delete(&models.PublicLink{}).Error
+}
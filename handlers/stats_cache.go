@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"projet1/database"
+	"projet1/pagination"
+	"projet1/response"
+	"projet1/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Nombre maximal de pages de résumé d'activité en cache (une par combinaison de paramètres)
+const maxCachedOverviews = 1000
+
+// Page de résumé d'activité en cache, avec son en-tête Link
+type overviewPage struct {
+	envelope pagination.Envelope
+	link     string
+}
+
+// Caches des statistiques, vidés à chaque écriture sur les tables dont elles dépendent
+var (
+	globalStatsCache = utils.NewTTLCache[response.UserStat](30*time.Second, 2)
+	overviewCache    = utils.NewTTLCache[overviewPage](30*time.Second, maxCachedOverviews)
+)
+
+// Tables lues par les statistiques en cache
+var statsTables = map[string]bool{"tasks": true, "users": true, "files": true, "blobs": true}
+
+// LoadStatsCache lit STATS_CACHE_TTL (durée Go, ex. 30s ou 2m ; 0 désactive le cache), 30 secondes par défaut
+func LoadStatsCache() error {
+	ttl := 30 * time.Second
+	if value := strings.TrimSpace(os.Getenv("STATS_CACHE_TTL")); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("STATS_CACHE_TTL %q invalide", value)
+		}
+		ttl = d
+	}
+	globalStatsCache = utils.NewTTLCache[response.UserStat](ttl, 2)
+	overviewCache = utils.NewTTLCache[overviewPage](ttl, maxCachedOverviews)
+	return nil
+}
+
+// RegisterStatsInvalidation vide les caches de statistiques après chaque création, modification
+// ou suppression faite avec GORM sur les tables qu'elles lisent, une fois la transaction de la
+// requête validée. Les écritures faites dans une transaction explicite passent par statsTransaction.
+// Les écritures d'une autre instance ne sont vues qu'à l'expiration du cache
+func RegisterStatsInvalidation(db *gorm.DB) error {
+	invalidate := func(tx *gorm.DB) {
+		if tx.Error == nil && statsTables[tx.Statement.Table] {
+			clearStatsCache()
+		}
+	}
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:commit_or_rollback_transaction").Register("stats:invalidate_create", invalidate); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:commit_or_rollback_transaction").Register("stats:invalidate_update", invalidate); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:commit_or_rollback_transaction").Register("stats:invalidate_delete", invalidate)
+}
+
+func clearStatsCache() {
+	globalStatsCache.Clear()
+	overviewCache.Clear()
+}
+
+// statsTransaction exécute fn dans une transaction puis vide les caches de statistiques :
+// dans une transaction explicite, les callbacks passent avant la validation et un calcul
+// concurrent pourrait remettre en cache l'état d'avant
+func statsTransaction(fn func(tx *gorm.DB) error) error {
+	defer clearStatsCache()
+	return database.DB.Transaction(fn)
+}
+
+// Clé du cache des résumés : l'URL (les liens de pagination en dépendent), plus l'utilisateur
+// connecté si la requête contient @me, qui désigne un utilisateur différent pour chacun
+func overviewCacheKey(c *gin.Context) string {
+	key := c.Request.URL.Path + "?" + c.Request.URL.RawQuery
+	if strings.Contains(c.Request.URL.RawQuery, "@me") || strings.Contains(c.Request.URL.RawQuery, "%40me") {
+		key += "#" + utils.CurrentUserID(c).String()
+	}
+	return key
+}
+
+// En-têtes de fraîcheur des statistiques : âge en secondes (Age), date du calcul et origine
+func setStatsFreshness(c *gin.Context, computedAt time.Time, cached bool) {
+	c.Header("Age", strconv.Itoa(int(time.Since(computedAt).Seconds())))
+	c.Header("X-Stats-Computed-At", computedAt.UTC().Format(time.RFC3339))
+	if cached {
+		c.Header("X-Stats-Cache", "hit")
+	} else {
+		c.Header("X-Stats-Cache", "miss")
+	}
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"projet1/blobs"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Durée de conservation des fichiers supprimés avant leur suppression définitive
+var trashRetention = 30 * 24 * time.Hour
+
+// loadTrash lit FILE_TRASH_RETENTION (durée Go, ex. "720h")
+func loadTrash() error {
+	trashRetention = 30 * 24 * time.Hour
+	if value := strings.TrimSpace(os.Getenv("FILE_TRASH_RETENTION")); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("FILE_TRASH_RETENTION %q invalide", value)
+		}
+		trashRetention = d
+	}
+	return nil
+}
+
+// @Summary Supprimer un fichier
+// @Description Place le fichier dans la corbeille : il n'est plus listé ni téléchargeable, et ne compte plus dans le quota. Il peut être restauré pendant FILE_TRASH_RETENTION, puis il est supprimé définitivement avec ses versions. Réservé au propriétaire (ou admin)
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Success		200				{object}	utils.AppSuccessCRUD
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable"
+// @Router /api/files/{file_id} [delete]
+func DeleteFile(c *gin.Context) {
+	file, ok := loadAccessibleFile(c, accessOwner)
+	if !ok {
+		return
+	}
+	if err := database.DB.Delete(&file).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordDelete, nil)
+}
+
+// @Summary Corbeille
+// @Description Fichiers supprimés de l'utilisateur connecté, encore restaurables, du plus récent au plus ancien
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Success		200				{array}		response.TrashedFile
+// @Failure		500				{object}	utils.AppError 				"Erreur Interne su serveur"
+// @Router /api/files/trash [get]
+func GetTrash(c *gin.Context) {
+	var files []models.File
+	err := database.DB.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", utils.CurrentUserID(c)).
+		Order("deleted_at DESC").Find(&files).Error
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	trash := make([]response.TrashedFile, 0, len(files))
+	for _, file := range files {
+		trash = append(trash, response.TrashedFile{
+			File:      file,
+			DeletedAt: file.DeletedAt.Time,
+			PurgeAt:   file.DeletedAt.Time.Add(trashRetention),
+		})
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, trash)
+}
+
+// @Summary Restaurer un fichier supprimé
+// @Description Sort un fichier de la corbeille avec toutes ses versions. Le quota du propriétaire est vérifié
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Success		200				{object}	models.File
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable dans la corbeille"
+// @Failure		413				{object}	utils.AppError 				"Quota dépassé"
+// @Router /api/files/{file_id}/restore [post]
+func RestoreFile(c *gin.Context) {
+	file, ok := loadTrashedFile(c)
+	if !ok {
+		return
+	}
+
+	err := statsTransaction(func(tx *gorm.DB) error {
+		//Le fichier et ses anciennes versions reviennent dans le quota
+		var stored int64
+		err := tx.Model(&models.FileVersion{}).Select("COALESCE(SUM(size), 0)").Where("file_id = ?", file.ID).Scan(&stored).Error
+		if err != nil {
+			return err
+		}
+		if err := checkQuota(tx, file.UserID, stored, true); err != nil {
+			return err
+		}
+		//Une purge concurrente a pu supprimer le fichier depuis son chargement
+		res := tx.Unscoped().Model(&file).Where("deleted_at IS NOT NULL").Update("deleted_at", nil)
+		if res.Error == nil && res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return res.Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return
+	}
+	if err != nil {
+		utils.JSONAppError(c, uploadAppError(err), err)
+		return
+	}
+	file.DeletedAt = gorm.DeletedAt{}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, file)
+}
+
+// @Summary Supprimer définitivement un fichier
+// @Description Supprime sans attendre un fichier de la corbeille, ses versions et les contenus qui ne sont plus utilisés
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Success		200				{object}	utils.AppSuccessCRUD
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable dans la corbeille"
+// @Router /api/files/trash/{file_id} [delete]
+func PurgeFile(c *gin.Context) {
+	file, ok := loadTrashedFile(c)
+	if !ok {
+		return
+	}
+	if err := purgeFile(c.Request.Context(), file); err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordDelete, nil)
+}
+
+// Fichier supprimé du paramètre file_id, si l'utilisateur connecté y a accès ; l'erreur est déjà envoyée si ok est faux
+func loadTrashedFile(c *gin.Context) (models.File, bool) {
+	var file models.File
+	fileID, err := uuid.Parse(c.Param("file_id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return file, false
+	}
+	if err := database.DB.Unscoped().First(&file, "id = ? AND deleted_at IS NOT NULL", fileID).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return file, false
+	}
+	if fileAccess(utils.CurrentUserID(c), file) < accessOwner {
+		utils.JSONAppError(c, utils.ErrAccessDenied, nil)
+		return file, false
+	}
+	return file, true
+}
+
+// purgeFile supprime définitivement un fichier : la ligne, ses versions, son texte et ses
+// liens de téléchargement et ses partages, puis les objets stockés qui ne sont plus référencés
+func purgeFile(ctx context.Context, file models.File) error {
+	var unused []models.Blob
+	err := statsTransaction(func(tx *gorm.DB) error {
+		//Le fichier disparaît d'abord : ses objets ne sont alors plus considérés comme utilisés
+		if err := tx.Unscoped().Delete(&models.File{}, "id = ?", file.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.FileText{}, "file_id = ?", file.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.DownloadLink{}, "file_id = ?", file.ID).Error; err != nil {
+			return err
+		}
+		if err := deleteResourceShares(tx, models.ResourceFile, file.ID); err != nil {
+			return err
+		}
+
+		var versions []models.FileVersion
+		if err := tx.Where("file_id = ?", file.ID).Find(&versions).Error; err != nil {
+			return err
+		}
+		for _, v := range versions {
+			if err := tx.Delete(&v).Error; err != nil {
+				return err
+			}
+			blob, err := blobs.Release(tx, v)
+			if err != nil {
+				return err
+			}
+			if blob != nil {
+				unused = append(unused, *blob)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if file.PreviewKey != "" {
+		unused = append(unused, models.Blob{StorageBackend: file.PreviewBackend, StorageKey: file.PreviewKey})
+	}
+	blobs.DeleteObjects(ctx, unused)
+	return nil
+}
+
+// PurgeDeletedFiles supprime définitivement les fichiers restés dans la corbeille plus de FILE_TRASH_RETENTION
+func PurgeDeletedFiles(ctx context.Context) (int, error) {
+	var files []models.File
+	err := database.DB.Unscoped().Where("deleted_at < ?", time.Now().Add(-trashRetention)).Find(&files).Error
+	if err != nil {
+		return 0, err
+	}
+	for i, file := range files {
+		if err := purgeFile(ctx, file); err != nil {
+			return i, err
+		}
+	}
+	return len(files), nil
+}
+
+// StartTrashJanitor vide régulièrement la corbeille des fichiers expirés
+func StartTrashJanitor(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			n, err := PurgeDeletedFiles(context.Background())
+			if err != nil {
+				log.Println("Erreur lors de la purge de la corbeille:", err)
+			} else if n > 0 {
+				log.Printf("%d fichier(s) supprimé(s) définitivement", n)
+			}
+		}
+	}()
+}
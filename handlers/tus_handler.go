@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"projet1/database"
+	"projet1/models"
+	"projet1/uploads"
+	"projet1/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Renvoyé par withUploadLock quand l'upload est déjà verrouillé (ou vient d'être supprimé)
+var errUploadLocked = errors.New("upload verrouillé")
+
+// Renvoyé quand Upload-Offset ne correspond pas aux octets reçus ou que l'upload est terminé
+var errUploadOffset = errors.New("Upload-Offset incorrect")
+
+// Durée du verrou d'un PATCH ; il est prolongé tant que l'envoi et la finalisation continuent,
+// et expire seul si l'instance qui le détient s'arrête
+const uploadLockTTL = time.Minute
+
+// withUploadLock exécute fn dans une transaction qui verrouille la ligne de l'upload
+// (SELECT ... FOR UPDATE) : un seul PATCH, une seule annulation ou purge à la fois par upload,
+// quelle que soit l'instance qui la reçoit. Un PATCH ne garde pas la transaction pendant l'envoi :
+// il y pose un verrou (LockToken, LockedUntil) que withUploadLock respecte aussi.
+// Les morceaux étant écrits dans UPLOAD_PARTS_DIR, ce répertoire doit être un volume partagé
+// par toutes les instances
+func withUploadLock(id uuid.UUID, fn func(tx *gorm.DB, upload *models.Upload) error) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var upload models.Upload
+		res := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("id = ?", id).Limit(1).Find(&upload)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 || upload.LockedUntil != nil && time.Now().Before(*upload.LockedUntil) {
+			return errUploadLocked
+		}
+		return fn(tx, &upload)
+	})
+}
+
+// Requête sur l'upload limitée au PATCH qui détient le verrou token
+func lockedUpload(id, token uuid.UUID) *gorm.DB {
+	return database.DB.Model(&models.Upload{}).Where("id = ? AND lock_token = ?", id, token)
+}
+
+// holdUploadLock prolonge le verrou token de l'upload jusqu'à l'appel de la fonction renvoyée, qui le relâche
+func holdUploadLock(id, token uuid.UUID) (release func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(uploadLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := lockedUpload(id, token).Update("locked_until", time.Now().Add(uploadLockTTL)).Error; err != nil {
+					log.Printf("upload %s: prolongation du verrou impossible: %v", id, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		if err := lockedUpload(id, token).Updates(map[string]interface{}{"lock_token": nil, "locked_until": nil}).Error; err != nil {
+			log.Printf("upload %s: libération du verrou impossible: %v", id, err)
+		}
+	}
+}
+
+// @Summary Capacités du serveur tus
+// @Description Découverte du protocole tus : version, extensions supportées (creation, termination, expiration) et taille maximale
+// @Tags Upload
+// @Success		204
+// @Header		204				{string}	Tus-Version			"1.0.0"
+// @Header		204				{string}	Tus-Extension		"creation,termination,expiration"
+// @Header		204				{int}		Tus-Max-Size		"Taille maximale d'un fichier"
+// @Router /api/uploads/ [options]
+func TusOptions(c *gin.Context) {
+	c.Header("Tus-Resumable", uploads.TusVersion)
+	c.Header("Tus-Version", uploads.TusVersion)
+	c.Header("Tus-Extension", uploads.TusExtensions)
+	c.Header("Tus-Max-Size", strconv.FormatInt(uploads.Current.MaxSize(), 10))
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Créer un upload en plusieurs morceaux
+// @Description Création d'un upload tus. Upload-Length est obligatoire, Upload-Metadata doit contenir filename (base64). Le nom et la taille sont vérifiés avec la politique d'upload ; le contenu l'est à la fin de l'upload
+// @Tags Upload
+// @Security BearerAuth
+// @Param		Tus-Resumable		header		string		true		"1.0.0"
+// @Param		Upload-Length		header		int			true		"Taille totale du fichier"
+// @Param		Upload-Metadata		header		string		true		"filename <base64>, filetype <base64>"
+// @Success		201
+// @Header		201				{string}	Location			"URL de l'upload"
+// @Header		201				{string}	Upload-Expires		"Date d'expiration"
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		412				{object}	utils.AppError 				"Version tus non supportée"
+// @Failure		413				{object}	utils.AppError 				"Fichier trop volumineux ou quota dépassé"
+// @Failure		415				{object}	utils.AppError 				"Type non autorisé"
+// @Router /api/uploads/ [post]
+func CreateUpload(c *gin.Context) {
+	if c.GetHeader("Upload-Defer-Length") != "" {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("Upload-Defer-Length n'est pas supporté"))
+		return
+	}
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("Upload-Length invalide"))
+		return
+	}
+
+	meta, err := uploads.ParseMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+
+	//Refus immédiat si le nom ou la taille annoncés sont hors politique
+	if err := uploads.Current.CheckDeclared(meta["filename"], length); err != nil {
+		utils.JSONAppError(c, uploadAppError(err), err)
+		return
+	}
+
+	if err := checkQuota(database.DB, utils.CurrentUserID(c), length, false); err != nil {
+		utils.JSONAppError(c, uploadAppError(err), err)
+		return
+	}
+
+	upload := models.Upload{
+		UserID:       utils.CurrentUserID(c),
+		FileName:     uploads.SanitizeFileName(meta["filename"]),
+		Metadata:     c.GetHeader("Upload-Metadata"),
+		UploadLength: length,
+		ExpiresAt:    time.Now().Add(uploads.Expiration).Truncate(time.Second),
+	}
+	if err := database.DB.Create(&upload).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	if err := uploads.CreatePart(upload.ID); err != nil {
+		database.DB.Delete(&upload)
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+
+	c.Header("Location", "/api/uploads/"+upload.ID.String())
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// @Summary Position d'un upload
+// @Description Renvoie le nombre d'octets déjà reçus (Upload-Offset) pour reprendre l'envoi. Upload-File-Id est présent quand l'upload est terminé
+// @Tags Upload
+// @Security BearerAuth
+// @Param		id					path		string		true		"ID de l'upload"
+// @Param		Tus-Resumable		header		string		true		"1.0.0"
+// @Success		200
+// @Header		200				{int}		Upload-Offset		"Octets reçus"
+// @Header		200				{int}		Upload-Length		"Taille totale"
+// @Header		200				{string}	Upload-File-Id		"ID du fichier créé"
+// @Failure		404				{object}	utils.AppError 				"Upload introuvable"
+// @Failure		410				{object}	utils.AppError 				"Upload expiré"
+// @Router /api/uploads/{id} [head]
+func HeadUpload(c *gin.Context) {
+	upload, ok := loadUpload(c)
+	if !ok {
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	uploadHeaders(c, upload)
+	c.Status(http.StatusOK)
+}
+
+// @Summary Envoyer un morceau
+// @Description Ajoute le corps de la requête à partir de Upload-Offset, qui doit être égal aux octets déjà reçus. Quand tout est reçu, le contenu est vérifié, stocké et un fichier est créé (Upload-File-Id)
+// @Tags Upload
+// @Security BearerAuth
+// @Accept		application/offset+octet-stream
+// @Param		id					path		string		true		"ID de l'upload"
+// @Param		Tus-Resumable		header		string		true		"1.0.0"
+// @Param		Upload-Offset		header		int			true		"Position du morceau"
+// @Success		204
+// @Header		204				{int}		Upload-Offset		"Octets reçus"
+// @Header		204				{string}	Upload-File-Id		"ID du fichier créé"
+// @Failure		404				{object}	utils.AppError 				"Upload introuvable"
+// @Failure		409				{object}	utils.AppError 				"Upload-Offset incorrect"
+// @Failure		410				{object}	utils.AppError 				"Upload expiré"
+// @Failure		413				{object}	utils.AppError 				"Morceau au-delà de Upload-Length ou quota dépassé"
+// @Failure		415				{object}	utils.AppError 				"Content-Type invalide ou contenu refusé"
+// @Failure		423				{object}	utils.AppError 				"Envoi déjà en cours"
+// @Router /api/uploads/{id} [patch]
+func PatchUpload(c *gin.Context) {
+	if c.ContentType() != "application/offset+octet-stream" {
+		utils.JSONAppError(c, utils.ErrInvalidContentType, nil)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		utils.JSONAppError(c, utils.ErrBadRequest, errors.New("Upload-Offset invalide"))
+		return
+	}
+
+	upload, ok := loadUpload(c)
+	if !ok {
+		return
+	}
+
+	//Réservation de l'upload dans une transaction courte. Le verrou posé est gardé pendant
+	//la réception du morceau et la finalisation, sans transaction ouverte
+	token := uuid.New()
+	err = withUploadLock(upload.ID, func(tx *gorm.DB, locked *models.Upload) error {
+		upload = *locked
+		if upload.FileID != nil || offset != upload.UploadOffset {
+			return errUploadOffset
+		}
+		return tx.Model(&upload).Updates(map[string]interface{}{
+			"lock_token":   token,
+			"locked_until": time.Now().Add(uploadLockTTL),
+		}).Error
+	})
+	switch {
+	case errors.Is(err, errUploadLocked):
+		utils.JSONAppError(c, utils.ErrUploadLocked, nil)
+		return
+	case errors.Is(err, errUploadOffset):
+		uploadHeaders(c, upload)
+		utils.JSONAppError(c, utils.ErrUploadOffsetMismatch, nil)
+		return
+	case err != nil:
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	defer holdUploadLock(upload.ID, token)()
+
+	remaining := upload.UploadLength - offset
+	if c.Request.ContentLength > remaining {
+		utils.JSONAppError(c, utils.ErrFileTooLarge, errors.New("le morceau dépasse Upload-Length"))
+		return
+	}
+
+	//Les octets écrits avant une coupure de connexion sont conservés : le client reprendra après
+	written, copyErr := uploads.AppendPart(upload.ID, offset, c.Request.Body, remaining)
+	if written > 0 {
+		expiresAt := time.Now().Add(uploads.Expiration).Truncate(time.Second)
+		res := lockedUpload(upload.ID, token).Updates(map[string]interface{}{
+			"upload_offset": offset + written,
+			"expires_at":    expiresAt,
+		})
+		if res.Error != nil {
+			utils.JSONAppError(c, utils.ErrInternal, res.Error)
+			return
+		}
+		//Verrou expiré et repris par un autre envoi : ces octets ne sont pas enregistrés
+		if res.RowsAffected == 0 {
+			utils.JSONAppError(c, utils.ErrUploadLocked, nil)
+			return
+		}
+		upload.UploadOffset, upload.ExpiresAt = offset+written, expiresAt
+	}
+	if copyErr != nil {
+		log.Printf("upload %s interrompu à %d octets: %v", upload.ID, upload.UploadOffset, copyErr)
+		utils.JSONAppError(c, utils.ErrInternal, copyErr)
+		return
+	}
+
+	if upload.UploadOffset == upload.UploadLength {
+		if err := finishUpload(c.Request.Context(), &upload, token); err != nil {
+			var reject *uploads.RejectError
+			if errors.As(err, &reject) {
+				//Contenu refusé : l'upload est abandonné
+				if derr := deleteUpload(database.DB, upload); derr != nil {
+					utils.JSONAppError(c, utils.ErrInternal, derr)
+					return
+				}
+			}
+			//Autres erreurs (quota dépassé...) : l'upload est conservé, un PATCH vide relancera la finalisation
+			utils.JSONAppError(c, uploadAppError(err), err)
+			return
+		}
+	}
+
+	uploadHeaders(c, upload)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Annuler un upload
+// @Description Supprime un upload et les morceaux déjà reçus (extension tus termination)
+// @Tags Upload
+// @Security BearerAuth
+// @Param		id					path		string		true		"ID de l'upload"
+// @Param		Tus-Resumable		header		string		true		"1.0.0"
+// @Success		204
+// @Failure		404				{object}	utils.AppError 				"Upload introuvable"
+// @Failure		423				{object}	utils.AppError 				"Envoi en cours"
+// @Router /api/uploads/{id} [delete]
+func DeleteUpload(c *gin.Context) {
+	upload, ok := loadUpload(c)
+	if !ok {
+		return
+	}
+	err := withUploadLock(upload.ID, func(tx *gorm.DB, locked *models.Upload) error {
+		return deleteUpload(tx, *locked)
+	})
+	if errors.Is(err, errUploadLocked) {
+		utils.JSONAppError(c, utils.ErrUploadLocked, nil)
+		return
+	}
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Upload de l'utilisateur connecté ; l'erreur est déjà envoyée si ok est faux
+func loadUpload(c *gin.Context) (models.Upload, bool) {
+	var upload models.Upload
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return upload, false
+	}
+	err = database.DB.First(&upload, "id = ? AND user_id = ?", id, utils.CurrentUserID(c)).Error
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return upload, false
+	}
+	if upload.FileID == nil && time.Now().After(upload.ExpiresAt) {
+		utils.JSONAppError(c, utils.ErrUploadExpired, nil)
+		return upload, false
+	}
+	return upload, true
+}
+
+func uploadHeaders(c *gin.Context, upload models.Upload) {
+	c.Header("Upload-Offset", strconv.FormatInt(upload.UploadOffset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.UploadLength, 10))
+	if upload.Metadata != "" {
+		c.Header("Upload-Metadata", upload.Metadata)
+	}
+	if upload.FileID != nil {
+		c.Header("Upload-File-Id", upload.FileID.String())
+	} else {
+		c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// Vérifie le contenu complet, le place dans le stockage et crée le models.File, qui prend l'ID
+// de l'upload : si file_id n'a pas pu être enregistré, la finalisation suivante retrouve ce
+// fichier au lieu d'en créer un second. L'appelant détient le verrou token de l'upload
+func finishUpload(ctx context.Context, upload *models.Upload, token uuid.UUID) error {
+	var file models.File
+	res := database.DB.Unscoped().Where("id = ?", upload.ID).Limit(1).Find(&file)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		part, err := uploads.OpenPart(upload.ID)
+		if err != nil {
+			return err
+		}
+		defer part.Close()
+
+		checked, err := uploads.Current.Check(upload.FileName, upload.UploadLength, part)
+		if err != nil {
+			return err
+		}
+		if _, err := part.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		if file, err = storeFile(ctx, part, upload.UploadLength, checked, upload.UserID, upload.ID, nil); err != nil {
+			return err
+		}
+	}
+	if err := lockedUpload(upload.ID, token).Update("file_id", file.ID).Error; err != nil {
+		return err
+	}
+
+	upload.FileID = &file.ID
+	if err := uploads.RemovePart(upload.ID); err != nil {
+		log.Printf("upload %s: suppression des morceaux impossible: %v", upload.ID, err)
+	}
+	return nil
+}
+
+// Supprime l'upload et ses morceaux ; tx doit verrouiller l'upload (withUploadLock),
+// sauf pour le PATCH qui détient son verrou
+func deleteUpload(tx *gorm.DB, upload models.Upload) error {
+	if err := uploads.RemovePart(upload.ID); err != nil {
+		return err
+	}
+	return tx.Delete(&upload).Error
+}
+
+// PurgeExpiredUploads supprime les uploads expirés et leurs morceaux (extension tus expiration).
+// Les fichiers créés par les uploads terminés sont conservés
+func PurgeExpiredUploads() (int, error) {
+	var expired []models.Upload
+	if err := database.DB.Where("expires_at < ?", time.Now()).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, upload := range expired {
+		//Verrou gardé jusqu'à la suppression ; un envoi en cours (verrou pris) prolongera l'upload
+		err := withUploadLock(upload.ID, func(tx *gorm.DB, locked *models.Upload) error {
+			if !time.Now().After(locked.ExpiresAt) {
+				return nil
+			}
+			if err := deleteUpload(tx, *locked); err != nil {
+				return err
+			}
+			purged++
+			return nil
+		})
+		if errors.Is(err, errUploadLocked) {
+			continue
+		}
+		if err != nil {
+			return purged, fmt.Errorf("upload %s: %w", upload.ID, err)
+		}
+	}
+	return purged, nil
+}
+
+// StartUploadJanitor lance la purge des uploads expirés à intervalle régulier
+func StartUploadJanitor(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			n, err := PurgeExpiredUploads()
+			if err != nil {
+				log.Println("Erreur lors de la purge des uploads expirés:", err)
+			} else if n > 0 {
+				log.Printf("%d upload(s) expiré(s) supprimé(s)", n)
+			}
+		}
+	}()
+}
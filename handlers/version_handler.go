@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"io"
+	"projet1/blobs"
+	"projet1/database"
+	"projet1/models"
+	"projet1/uploads"
+	"projet1/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// @Summary Envoyer une nouvelle version d'un fichier
+// @Description Remplace le contenu d'un fichier par une nouvelle version sous le même ID. Les versions précédentes restent consultables (FILE_MAX_VERSIONS au maximum, les plus anciennes sont supprimées). Mêmes vérifications que l'upload : type, taille, quota (du propriétaire). Propriétaire, admin ou partage en modification
+// @Tags Fichier
+// @Security BearerAuth
+// @Accept 		multipart/form-data
+// @Produce		json
+// @Param		file_id			path		string			true		"File ID"
+// @Param		file			formData	file			true		"Nouvelle version"
+// @Success		201				{object}	models.File
+// @Failure		400				{object}	utils.AppError 				"Requête invalide"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable"
+// @Failure		413				{object}	utils.AppError 				"Fichier trop volumineux ou quota dépassé"
+// @Failure		415				{object}	utils.AppError 				"Type non autorisé ou extension incohérente"
+// @Router /api/files/{file_id}/versions [post]
+func UploadFileVersion(c *gin.Context) {
+	file, ok := loadAccessibleFile(c, accessEdit)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	src, err := header.Open()
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	defer src.Close()
+
+	checked, err := uploads.Current.Check(header.Filename, header.Size, src)
+	if err != nil {
+		utils.JSONAppError(c, uploadAppError(err), err)
+		return
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	if err := checkQuota(database.DB, file.UserID, header.Size, false); err != nil {
+		utils.JSONAppError(c, uploadAppError(err), err)
+		return
+	}
+
+	updated, err := storeFile(c.Request.Context(), src, header.Size, checked, utils.CurrentUserID(c), uuid.Nil, &file)
+	if err != nil {
+		utils.JSONAppError(c, uploadAppError(err), err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordCreated, updated)
+}
+
+// @Summary Lister les versions d'un fichier
+// @Description Versions conservées d'un fichier, de la plus récente à la plus ancienne ; current indique la version servie par défaut
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Success		200				{array}		models.FileVersion
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier introuvable"
+// @Router /api/files/{file_id}/versions [get]
+func GetFileVersions(c *gin.Context) {
+	file, ok := loadAccessibleFile(c, accessRead)
+	if !ok {
+		return
+	}
+
+	var versions []models.FileVersion
+	if err := database.DB.Where("file_id = ?", file.ID).Order("version DESC").Find(&versions).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	for i := range versions {
+		versions[i].Current = versions[i].Version == file.Version
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, versions)
+}
+
+// @Summary Télécharger une version d'un fichier
+// @Description Contenu d'une version précise d'un fichier
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param		file_id			path		string			true		"File ID"
+// @Param		version			path		int				true		"Numéro de version"
+// @Param		inline			query		bool			false		"Afficher dans le navigateur au lieu de télécharger"
+// @Param		Range			header		string			false		"Plage d'octets (ex. bytes=0-1023)"
+// @Success		200				{file}		string						"File Content"
+// @Success		206				{file}		string						"Partial Content"
+// @Success		304				{string}	string						"Not Modified"
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier ou version introuvable"
+// @Router /api/files/{file_id}/versions/{version} [get]
+func DownloadFileVersion(c *gin.Context) {
+	file, ok := loadAccessibleFile(c, accessRead)
+	if !ok {
+		return
+	}
+	version, ok := loadFileVersion(c, file)
+	if !ok {
+		return
+	}
+	version.Apply(&file)
+	serveStoredFile(c, file)
+}
+
+// @Summary Restaurer une ancienne version
+// @Description Crée une nouvelle version courante avec le contenu d'une version précédente (l'historique est conservé). Le quota du propriétaire est vérifié
+// @Tags Fichier
+// @Security BearerAuth
+// @Produce json
+// @Param		file_id			path		string			true		"File ID"
+// @Param		version			path		int				true		"Numéro de version à restaurer"
+// @Success		200				{object}	models.File
+// @Failure		403				{object}	utils.AppError 				"Accès refusé"
+// @Failure		404				{object}	utils.AppError 				"Fichier ou version introuvable"
+// @Failure		413				{object}	utils.AppError 				"Quota dépassé"
+// @Router /api/files/{file_id}/versions/{version}/promote [post]
+func PromoteFileVersion(c *gin.Context) {
+	file, ok := loadAccessibleFile(c, accessEdit)
+	if !ok {
+		return
+	}
+	source, ok := loadFileVersion(c, file)
+	if !ok {
+		return
+	}
+
+	//Déjà la version courante : rien à faire
+	if source.Version == file.Version {
+		utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, file)
+		return
+	}
+
+	promoted := source
+	promoted.ID, promoted.CreatedAt = uuid.Nil, time.Time{}
+	promoted.UserID = utils.CurrentUserID(c)
+	promoted.PromotedFrom = &source.Version
+
+	var pruned []models.Blob
+	err := statsTransaction(func(tx *gorm.DB) error {
+		if err := checkQuota(tx, file.UserID, source.Size, true); err != nil {
+			return err
+		}
+		//La nouvelle version partage le blob de la version restaurée
+		if source.Checksum != "" {
+			err := tx.Model(&models.Blob{}).Where("hash = ?", source.Checksum).
+				Update("ref_count", gorm.Expr("ref_count + 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		pruned, err = saveVersion(tx, &file, true, &promoted)
+		return err
+	})
+	if err != nil {
+		utils.JSONAppError(c, uploadAppError(err), err)
+		return
+	}
+	blobs.DeleteObjects(c.Request.Context(), pruned)
+	queueFileJobs(file)
+
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, file)
+}
+
+// Version demandée (paramètre version) d'un fichier ; l'erreur est déjà envoyée si ok est faux
+func loadFileVersion(c *gin.Context, file models.File) (models.FileVersion, bool) {
+	var version models.FileVersion
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return version, false
+	}
+	if err := database.DB.First(&version, "file_id = ? AND version = ?", file.ID, number).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrRecordNotFound, err)
+		return version, false
+	}
+	version.Current = version.Version == file.Version
+	return version, true
+}
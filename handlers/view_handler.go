@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"errors"
+	"projet1/database"
+	"projet1/filters"
+	"projet1/models"
+	"projet1/pagination"
+	"projet1/response"
+	"projet1/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Listes intelligentes : des vues prédéfinies exécutées comme les vues enregistrées
+var smartViews = []models.SavedView{
+	{Key: "today", Name: "Aujourd'hui", Filter: "user_id:eq:@me,completed:eq:false,due_date:between:@today|@today+1d", Sort: "due_date", BuiltIn: true},
+	{Key: "overdue", Name: "En retard", Filter: "user_id:eq:@me,completed:eq:false,due_date:lt:@now", Sort: "due_date", BuiltIn: true},
+	{Key: "recently_completed", Name: "Terminées récemment", Filter: "user_id:eq:@me,completed:eq:true,completed_at:gt:@today-7d", Sort: "-completed_at", BuiltIn: true},
+}
+
+// Champs autorisés pour le regroupement et la clé de groupe de chaque tâche
+var viewGroupKeys = map[string]func(t models.Task) any{
+	"completed":  func(t models.Task) any { return t.Completed },
+	"user_id":    func(t models.Task) any { return t.UserID },
+	"project_id": func(t models.Task) any { return t.ProjectID },
+}
+
+// @Summary Créer une vue
+// @Description Enregistrer une requête de tâches nommée (filtre, tri, regroupement), éventuellement partagée avec des coéquipiers
+// @Tags Vue
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		view		body		response.SavedViewRequest		true		"La définition de la vue"
+// @Success		201			{object}	utils.AppSuccessCRUD
+// @Failure		400			{object}	utils.AppError 				"Requête invalide"
+// @Failure		500			{object}	utils.AppError 				"Erreur interne"
+// @Router /api/views/ [post]
+func CreateView(c *gin.Context) {
+	var req response.SavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	if err := validateView(req); err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidFilter, err)
+		return
+	}
+
+	view := models.SavedView{
+		Name:    req.Name,
+		Filter:  req.Filter,
+		Sort:    req.Sort,
+		GroupBy: req.GroupBy,
+		UserID:  utils.CurrentUserID(c),
+	}
+	view.Shares = viewShares(view.ID, req.SharedWith)
+
+	if err := database.DB.Create(&view).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordCreated, view)
+}
+
+// @Summary Extraire les vues
+// @Description Extraire les listes intelligentes, les vues de l'utilisateur et celles partagées avec lui
+// @Tags Vue
+// @Security BearerAuth
+// @Produce json
+// @Success		200			{object}	utils.AppSuccessCRUD
+// @Failure		500			{object}	utils.AppError 				"Erreur interne"
+// @Router /api/views/ [get]
+func GetViews(c *gin.Context) {
+	me := utils.CurrentUserID(c)
+
+	var views []models.SavedView
+	shared := database.DB.Model(&models.SavedViewShare{}).Select("saved_view_id").Where("user_id = ?", me)
+	if err := database.DB.Preload("Shares").Where("user_id = ?", me).Or("id IN (?)", shared).Find(&views).Error; err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+
+	all := append([]models.SavedView{}, smartViews...)
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, append(all, views...))
+}
+
+// @Summary Extraire une vue
+// @Description Extraire une vue par son ID ou la clé d'une liste intelligente (today, overdue, recently_completed)
+// @Tags Vue
+// @Security BearerAuth
+// @Produce json
+// @Param		id 			path		string			true		"ID de la vue ou clé de la liste intelligente"
+// @Success		200			{object}	utils.AppSuccessCRUD
+// @Failure		403			{object}	utils.AppError 				"Accès refusé"
+// @Failure		404			{object}	utils.AppError 				"Vue introuvable"
+// @Router /api/views/{id} [get]
+func GetView(c *gin.Context) {
+	view, appErr, err := loadView(c)
+	if err != nil {
+		utils.JSONAppError(c, appErr, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, view)
+}
+
+// @Summary Mettre à jour une vue
+// @Description Mettre à jour une vue et la liste des coéquipiers avec qui elle est partagée (propriétaire uniquement)
+// @Tags Vue
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param		id 			path		string						true		"ID de la vue"
+// @Param		view		body		response.SavedViewRequest		true		"La nouvelle définition de la vue"
+// @Success		200			{object}	utils.AppSuccessCRUD
+// @Failure		400			{object}	utils.AppError 				"Requête invalide"
+// @Failure		403			{object}	utils.AppError 				"Accès refusé"
+// @Failure		404			{object}	utils.AppError 				"Vue introuvable"
+// @Router /api/views/{id} [put]
+func UpdateView(c *gin.Context) {
+	view, appErr, err := loadOwnedView(c)
+	if err != nil {
+		utils.JSONAppError(c, appErr, err)
+		return
+	}
+
+	var req response.SavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONAppError(c, utils.ErrBadRequest, err)
+		return
+	}
+	if err := validateView(req); err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidFilter, err)
+		return
+	}
+
+	view.Name, view.Filter, view.Sort, view.GroupBy = req.Name, req.Filter, req.Sort, req.GroupBy
+	view.Shares = viewShares(view.ID, req.SharedWith)
+
+	//Les partages sont remplacés dans la même transaction que la vue
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Shares").Save(&view).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("saved_view_id = ?", view.ID).Delete(&models.SavedViewShare{}).Error; err != nil {
+			return err
+		}
+		if len(view.Shares) > 0 {
+			return tx.Create(&view.Shares).Error
+		}
+		return nil
+	})
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordUpdated, view)
+}
+
+// @Summary Supprimer une vue
+// @Description Supprimer une vue par son ID (propriétaire uniquement)
+// @Tags Vue
+// @Security BearerAuth
+// @Produce json
+// @Param		id 			path		string			true		"ID de la vue"
+// @Success		200			{object}	utils.AppSuccessCRUD
+// @Failure		403			{object}	utils.AppError 				"Accès refusé"
+// @Failure		404			{object}	utils.AppError 				"Vue introuvable"
+// @Router /api/views/{id} [delete]
+func DeleteView(c *gin.Context) {
+	view, appErr, err := loadOwnedView(c)
+	if err != nil {
+		utils.JSONAppError(c, appErr, err)
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("saved_view_id = ?", view.ID).Delete(&models.SavedViewShare{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&view).Error
+	})
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordDelete, nil)
+}
+
+// @Summary Exécuter une vue
+// @Description Extraire les tâches correspondant à une vue ou une liste intelligente, avec pagination. Si la vue a un regroupement, les éléments de la page sont des groupes
+// @Tags Vue
+// @Security BearerAuth
+// @Produce json
+// @Param		id 			path		string		true		"ID de la vue ou clé de la liste intelligente"
+// @Param   	page 		query		string 		false		"Les pages (mode offset)"
+// @Param		cursor		query		string		false		"Curseur opaque (vues sans tri ni regroupement)"
+// @Param		limit		query		string		false		"La limite des elements (max 100)"
+// @Param		total		query		bool		false		"Inclure le nombre total"
+// @Success		200 		{object}	pagination.Envelope
+// @Failure		400			{object}	utils.AppError 				"Requête invalide"
+// @Failure		403			{object}	utils.AppError 				"Accès refusé"
+// @Failure		404			{object}	utils.AppError 				"Vue introuvable"
+// @Router /api/views/{id}/tasks [get]
+func GetViewTasks(c *gin.Context) {
+	view, appErr, err := loadView(c)
+	if err != nil {
+		utils.JSONAppError(c, appErr, err)
+		return
+	}
+
+	p, err := pagination.FromContext(c)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidPagination, err)
+		return
+	}
+
+	//Le champ de regroupement passe en premier dans le tri pour que les groupes soient contigus
+	sort := view.Sort
+	if view.GroupBy != "" {
+		sort = view.GroupBy
+		if view.Sort != "" {
+			sort += "," + view.Sort
+		}
+	}
+
+	q, err := filters.ParseAs(filters.TaskSpec, view.Filter, sort, utils.CurrentUserID(c))
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInvalidFilter, err)
+		return
+	}
+	if p.Keyset && q.HasSort() {
+		utils.JSONAppError(c, utils.ErrInvalidPagination, &pagination.ParamError{Param: "cursor", Reason: "non disponible pour une vue triée"})
+		return
+	}
+
+	page, err := pagination.Find[models.Task](c, database.DB.Model(&models.Task{}).Scopes(q.Scope), "tasks", p)
+	if err != nil {
+		utils.JSONAppError(c, utils.ErrInternal, err)
+		return
+	}
+	if view.GroupBy != "" {
+		page.Items = groupTasks(page.Items.([]models.Task), viewGroupKeys[view.GroupBy])
+	}
+
+	utils.JSONAppSuccessCRUD(c, utils.SuccessRecordFetched, page)
+}
+
+// Charge une vue lisible par l'utilisateur : liste intelligente, vue propre ou partagée
+func loadView(c *gin.Context) (models.SavedView, utils.AppError, error) {
+	for _, v := range smartViews {
+		if v.Key == c.Param("id") {
+			return v, utils.AppError{}, nil
+		}
+	}
+
+	view, appErr, err := findView(c)
+	if err != nil {
+		return view, appErr, err
+	}
+
+	me := utils.CurrentUserID(c)
+	if view.UserID == me {
+		return view, appErr, nil
+	}
+	for _, share := range view.Shares {
+		if share.UserID == me {
+			return view, appErr, nil
+		}
+	}
+	return view, utils.ErrAccessDenied, errors.New("vue non partagée avec cet utilisateur")
+}
+
+// Charge une vue modifiable : seul le propriétaire peut la modifier ou la supprimer
+func loadOwnedView(c *gin.Context) (models.SavedView, utils.AppError, error) {
+	view, appErr, err := findView(c)
+	if err != nil {
+		return view, appErr, err
+	}
+	if view.UserID != utils.CurrentUserID(c) {
+		return view, utils.ErrAccessDenied, errors.New("seul le propriétaire peut modifier la vue")
+	}
+	return view, appErr, nil
+}
+
+func findView(c *gin.Context) (models.SavedView, utils.AppError, error) {
+	var view models.SavedView
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return view, utils.ErrBadRequest, err
+	}
+	if err := database.DB.Preload("Shares").First(&view, "id = ?", id).Error; err != nil {
+		return view, utils.ErrRecordNotFound, err
+	}
+	return view, utils.AppError{}, nil
+}
+
+// Vérifie le filtre, le tri et le regroupement avant l'enregistrement
+func validateView(req response.SavedViewRequest) error {
+	if req.GroupBy != "" {
+		if _, ok := viewGroupKeys[req.GroupBy]; !ok {
+			return &filters.ParseError{Param: "group_by", Expr: req.GroupBy, Reason: "regroupement non autorisé"}
+		}
+	}
+	//@me est résolu à l'exécution, un ID quelconque suffit pour la validation
+	_, err := filters.ParseAs(filters.TaskSpec, req.Filter, req.Sort, uuid.New())
+	return err
+}
+
+// Partages d'une vue, un par utilisateur : un doublon violerait la clé primaire de saved_view_shares
+func viewShares(viewID uuid.UUID, userIDs []uuid.UUID) []models.SavedViewShare {
+	var shares []models.SavedViewShare
+	seen := map[uuid.UUID]bool{}
+	for _, userID := range userIDs {
+		if !seen[userID] {
+			seen[userID] = true
+			shares = append(shares, models.SavedViewShare{SavedViewID: viewID, UserID: userID})
+		}
+	}
+	return shares
+}
+
+// Regroupe les tâches (déjà triées par le champ de regroupement) en conservant l'ordre
+func groupTasks(tasks []models.Task, key func(models.Task) any) []response.TaskGroup {
+	groups := []response.TaskGroup{}
+	index := map[any]int{}
+	for _, t := range tasks {
+		k := key(t)
+		//Les pointeurs (project_id) sont comparés par valeur
+		if id, ok := k.(*uuid.UUID); ok {
+			k = nil
+			if id != nil {
+				k = *id
+			}
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, response.TaskGroup{Key: k, Tasks: []models.Task{}})
+		}
+		groups[i].Tasks = append(groups[i].Tasks, t)
+		groups[i].Count++
+	}
+	return groups
+}
@@ -0,0 +1,97 @@
+// File d'attente en mémoire pour les traitements de fichiers en arrière-plan
+// (aperçus, extraction de texte...). L'état de chaque traitement est enregistré sur
+// le fichier concerné : un travail perdu (redémarrage, file pleine) est relancé au démarrage
+package jobs
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Handler traite un fichier ; l'erreur est seulement journalisée, le handler enregistre lui-même son statut
+type Handler func(ctx context.Context, fileID uuid.UUID) error
+
+type job struct {
+	kind   string
+	fileID uuid.UUID
+}
+
+var (
+	mu       sync.RWMutex
+	handlers = map[string]Handler{}
+	queue    = make(chan job, 1000)
+)
+
+// Register associe un type de travail à son handler
+func Register(kind string, h Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[kind] = h
+}
+
+// Enqueue ajoute un travail sans bloquer ; renvoie false si la file est pleine
+func Enqueue(kind string, fileID uuid.UUID) bool {
+	select {
+	case queue <- job{kind: kind, fileID: fileID}:
+		return true
+	default:
+		log.Printf("jobs: file pleine, %s de %s reporté au prochain démarrage", kind, fileID)
+		return false
+	}
+}
+
+// EnqueueWait ajoute un travail en attendant une place dans la file (reprise au démarrage)
+func EnqueueWait(ctx context.Context, kind string, fileID uuid.UUID) error {
+	select {
+	case queue <- job{kind: kind, fileID: fileID}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start lance JOB_WORKERS workers (2 par défaut) jusqu'à l'annulation de ctx
+func Start(ctx context.Context) {
+	workers, err := strconv.Atoi(os.Getenv("JOB_WORKERS"))
+	if err != nil || workers <= 0 {
+		workers = 2
+	}
+	for i := 0; i < workers; i++ {
+		go work(ctx)
+	}
+}
+
+func work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-queue:
+			run(ctx, j)
+		}
+	}
+}
+
+func run(ctx context.Context, j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("jobs: %s de %s: panic: %v", j.kind, j.fileID, r)
+		}
+	}()
+
+	mu.RLock()
+	h, ok := handlers[j.kind]
+	mu.RUnlock()
+	if !ok {
+		log.Printf("jobs: type de travail inconnu %q", j.kind)
+		return
+	}
+	if err := h(ctx, j.fileID); err != nil {
+		log.Printf("jobs: %s de %s: %v", j.kind, j.fileID, err)
+	}
+}
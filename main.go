@@ -1,46 +1,99 @@
-// @title API Utilisateurs et Tâches
-// @version 1.0
-// @description Cette API permet de gérer les utilisateurs et leurs tâches
-// @contact.name Développeur API
-// @contact.email mayssa@gmail.com
-// @host localhost:8080
-// @BasePath /
-// @securityDefinitions.apikey BearerAuth
-// @in header
-// @name Authorization
-// @description Saisir le token JWT comme suit : Bearer <token>
-
-package main
-
-import (
-	"projet1/database"
-	"projet1/middleware"
-	"projet1/models"
-	"projet1/routes"
-
-	_ "projet1/docs"
-
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-)
-
-func main() {
-
-	godotenv.Load()
-	database.Connect()
-
-	database.DB.AutoMigrate(&models.User{}, &models.Task{}, &models.File{})
-
-	r := gin.Default()
-
-	//L'application globale du middleware CORS
-	r.Use(middleware.CORSMiddleware())
-
-	routes.SetupRouter(r)
-	r.Static("/files", "./upload")
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	r.Run(":8080")
-}
+// @title API Utilisateurs et Tâches
+// @version 1.0
+// @description Cette API permet de gérer les utilisateurs et leurs tâches
+// @contact.name Développeur API
+// @contact.email mayssa@gmail.com
+// @host localhost:8080
+// @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Saisir le token JWT comme suit : Bearer <token>
+
+package main
+
+import (
+	"context"
+	"log"
+	"projet1/database"
+	"projet1/extract"
+	"projet1/handlers"
+	"projet1/jobs"
+	"projet1/middleware"
+	"projet1/models"
+	"projet1/previews"
+	"projet1/routes"
+	"projet1/scan"
+	"projet1/storage"
+	"projet1/uploads"
+	"time"
+	_ "time/tzdata" //Fuseaux horaires des statistiques, même sans base tz dans l'image
+
+	_ "projet1/docs"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+func main() {
+
+	godotenv.Load()
+	database.Connect()
+
+	database.DB.AutoMigrate(&models.User{}, &models.Task{}, &models.File{}, &models.Project{}, &models.Tag{}, &models.SavedView{}, &models.SavedViewShare{}, &models.DownloadLink{}, &models.Upload{}, &models.Blob{}, &models.UserQuota{}, &models.RoleQuota{}, &models.FileVersion{}, &models.FileText{}, &models.Share{}, &models.PublicLink{}, &models.UserTaskStats{}, &models.Report{})
+	if err := database.MigrateSearch(); err != nil {
+		log.Fatal("Erreur lors de la migration de la recherche plein texte:", err)
+	}
+	if err := database.MigrateFileStorage(); err != nil {
+		log.Fatal("Erreur lors de la migration du stockage des fichiers:", err)
+	}
+	if err := database.MigrateTaskDates(); err != nil {
+		log.Fatal("Erreur lors de la migration des dates des tâches:", err)
+	}
+	if err := database.MigrateTaskStats(); err != nil {
+		log.Fatal("Erreur lors de la migration des compteurs de tâches:", err)
+	}
+	if err := handlers.LoadStatsCache(); err != nil {
+		log.Fatal("Configuration du cache des statistiques invalide:", err)
+	}
+	if err := handlers.LoadReports(); err != nil {
+		log.Fatal("Configuration des rapports invalide:", err)
+	}
+	if err := handlers.RegisterStatsInvalidation(database.DB); err != nil {
+		log.Fatal("Erreur lors de l'enregistrement de l'invalidation des statistiques:", err)
+	}
+	storage.Connect()
+	if err := uploads.Load(); err != nil {
+		log.Fatal("Configuration des uploads invalide:", err)
+	}
+	if err := handlers.LoadFiles(); err != nil {
+		log.Fatal("Configuration des fichiers invalide:", err)
+	}
+	if err := previews.Load(); err != nil {
+		log.Fatal("Configuration des aperçus invalide:", err)
+	}
+	if err := extract.Load(); err != nil {
+		log.Fatal("Configuration de l'extraction du texte invalide:", err)
+	}
+	if err := scan.Load(); err != nil {
+		log.Fatal("Configuration de l'antivirus invalide:", err)
+	}
+	handlers.StartUploadJanitor(time.Hour)
+	handlers.StartTrashJanitor(time.Hour)
+	handlers.StartReportJanitor(time.Hour)
+	jobs.Start(context.Background())
+	handlers.StartFileJobs(context.Background())
+	handlers.StartReportJobs(context.Background())
+
+	r := gin.Default()
+
+	//L'application globale du middleware CORS
+	r.Use(middleware.CORSMiddleware())
+
+	routes.SetupRouter(r)
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	r.Run(":8080")
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"projet1/uploads"
+	"projet1/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TusMiddleware vérifie la version du protocole tus demandée par le client
+// et ajoute l'en-tête Tus-Resumable à toutes les réponses
+func TusMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Tus-Resumable", uploads.TusVersion)
+		if c.Request.Method != http.MethodOptions && c.GetHeader("Tus-Resumable") != uploads.TusVersion {
+			c.Header("Tus-Version", uploads.TusVersion)
+			utils.JSONAppError(c, utils.ErrTusVersion, nil)
+			c.Abort() //pour bloquer
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"projet1/uploads"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTusMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TusMiddleware())
+	router.Any("/uploads", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		name        string
+		method      string
+		version     string
+		wantStatus  int
+		wantVersion bool //en-tête Tus-Version (versions supportées) attendu
+	}{
+		{"version supportée", http.MethodPatch, uploads.TusVersion, http.StatusNoContent, false},
+		{"sans version", http.MethodPatch, "", http.StatusPreconditionFailed, true},
+		{"autre version", http.MethodPost, "0.2.2", http.StatusPreconditionFailed, true},
+		{"OPTIONS sans version", http.MethodOptions, "", http.StatusNoContent, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/uploads", nil)
+			if tt.version != "" {
+				req.Header.Set("Tus-Resumable", tt.version)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("statut %d, attendu %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Tus-Resumable"); got != uploads.TusVersion {
+				t.Errorf("Tus-Resumable = %q, attendu %q", got, uploads.TusVersion)
+			}
+			if got := w.Header().Get("Tus-Version") != ""; got != tt.wantVersion {
+				t.Errorf("Tus-Version présent = %v, attendu %v", got, tt.wantVersion)
+			}
+		})
+	}
+}
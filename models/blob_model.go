@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Statuts d'un blob après vérification
+const (
+	BlobOK      = "ok"
+	BlobMissing = "missing" //Objet introuvable dans le stockage
+	BlobCorrupt = "corrupt" //Contenu différent de la somme de contrôle
+)
+
+// Contenu stocké une seule fois, partagé par tous les fichiers de même SHA-256
+type Blob struct {
+	Hash           string     `gorm:"type:char(64);primarykey" json:"hash"` //SHA-256 en hexadécimal
+	Size           int64      `gorm:"type:bigint" json:"size"`
+	StorageBackend string     `gorm:"type:varchar(20)" json:"storage_backend"`
+	StorageKey     string     `gorm:"type:varchar(255)" json:"storage_key"`
+	RefCount       int        `gorm:"not null;default:0" json:"ref_count"` //Nombre de fichiers qui utilisent ce contenu
+	Status         string     `gorm:"type:varchar(20);default:ok" json:"status"`
+	VerifiedAt     *time.Time `json:"verified_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Lien de téléchargement signé et temporaire vers un fichier
+type DownloadLink struct {
+	BaseModel
+	ID        uuid.UUID  `gorm:"type:uuid;primarykey" json:"id"`
+	FileID    uuid.UUID  `gorm:"type:uuid;index" json:"file_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid" json:"user_id"` //Utilisateur qui a généré le lien
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+func (l *DownloadLink) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.New()
+	return
+}
@@ -1,22 +1,86 @@
-package models
-
-import (
-	"github.com/google/uuid"
-	"gorm.io/gorm"
-)
-
-type File struct {
-	BaseModel
-	ID       uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
-	FileName string    `gorm:"type:varchar(100)" json:"file_name"`
-	FileType string    `gorm:"type:varchar(100)" json:"file_type"`
-	Size     int64     `gorm:"type:int" json:"file_size"`
-	Path     string    `gorm:"type:varchar(255)" json:"path"` //Local path au niveau du projet
-	URL      string    `gorm:"type:varchar(255)" json:"URL"`  //Accessible via HTTP
-	UserID   uuid.UUID `gorm:"type:uuid" json:"user_id"`
-}
-
-func (f *File) BeforeCreate(tx *gorm.DB) (err error) {
-	f.ID = uuid.New()
-	return
-}
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type File struct {
+	BaseModel
+	ID       uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
+	FileName string    `gorm:"type:varchar(100)" json:"file_name"`
+	FileType string    `gorm:"type:varchar(100)" json:"file_type"`
+	MimeType string    `gorm:"type:varchar(100)" json:"mime_type"` //Type détecté à partir du contenu
+	Size     int64     `gorm:"type:bigint" json:"file_size"`
+	Path     string    `gorm:"type:varchar(255)" json:"path"` //Local path au niveau du projet (anciens fichiers)
+	URL      string    `gorm:"type:varchar(255)" json:"URL"`  //Accessible via HTTP
+	UserID   uuid.UUID `gorm:"type:uuid" json:"user_id"`
+
+	StorageBackend string `gorm:"type:varchar(20)" json:"-"`              //local ou s3
+	StorageKey     string `gorm:"type:varchar(255)" json:"-"`             //Clé de l'objet dans le backend
+	Checksum       string `gorm:"type:varchar(64);index" json:"checksum"` //SHA-256 du contenu (Blob)
+	Version        int    `gorm:"not null;default:1" json:"version"`      //Numéro de la version courante
+
+	PreviewStatus  string `gorm:"type:varchar(20);not null;default:pending;index" json:"preview_status"` //pending, ready, failed ou unsupported
+	PreviewError   string `gorm:"type:text" json:"preview_error,omitempty"`
+	PreviewBackend string `gorm:"type:varchar(20)" json:"-"`
+	PreviewKey     string `gorm:"type:varchar(255)" json:"-"` //Aperçu PNG de la version courante
+	PreviewURL     string `gorm:"-" json:"preview_url,omitempty"`
+
+	TextStatus string `gorm:"type:varchar(20);not null;default:pending;index" json:"text_status"` //Extraction du texte : pending, ready, failed ou unsupported
+	TextError  string `gorm:"type:text" json:"text_error,omitempty"`
+
+	ScanStatus    string     `gorm:"type:varchar(20);not null;default:pending;index" json:"scan_status"` //Analyse antivirus de la version courante
+	ScanSignature string     `gorm:"type:varchar(255)" json:"scan_signature,omitempty"`                  //Menace détectée
+	ScannedAt     *time.Time `json:"scanned_at,omitempty"`
+}
+
+// Statuts de l'analyse antivirus ; le fichier reste en quarantaine tant qu'il n'est pas clean
+const (
+	ScanPending  = "pending"
+	ScanClean    = "clean"
+	ScanInfected = "infected"
+	ScanFailed   = "failed"
+)
+
+// Statuts des traitements en arrière-plan (aperçu, extraction du texte)
+const (
+	ProcessingPending     = "pending"
+	ProcessingReady       = "ready"
+	ProcessingFailed      = "failed"
+	ProcessingUnsupported = "unsupported"
+)
+
+func (f *File) BeforeCreate(tx *gorm.DB) (err error) {
+	//L'ID peut être généré avant la création pour nommer l'objet stocké
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
+
+// ResetDerived marque l'aperçu et le texte à régénérer pour une nouvelle version courante
+func (f *File) ResetDerived() {
+	f.PreviewStatus, f.PreviewError, f.PreviewURL = ProcessingPending, "", ""
+	f.TextStatus, f.TextError = ProcessingPending, ""
+}
+
+// Quarantined indique que le contenu ne peut pas être servi (analyse en attente, échouée ou menace détectée)
+func (f File) Quarantined() bool {
+	return f.ScanStatus != ScanClean
+}
+
+// L'URL de l'aperçu n'est renseignée que lorsqu'il est disponible
+func (f *File) AfterFind(tx *gorm.DB) (err error) {
+	if f.PreviewStatus == ProcessingReady {
+		f.PreviewURL = "/api/files/" + f.ID.String() + "/preview"
+	}
+	return
+}
+
+// Clé de pagination par curseur (created_at, id)
+func (f File) CursorKey() (time.Time, uuid.UUID) {
+	return f.CreatedAt, f.ID
+}
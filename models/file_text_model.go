@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Texte extrait d'un document pour la recherche plein texte (colonnes search_fr / search_en
+// ajoutées par database.MigrateSearch). Seul le texte de la version courante est conservé
+type FileText struct {
+	FileID    uuid.UUID `gorm:"type:uuid;primarykey" json:"file_id"`
+	Version   int       `gorm:"not null" json:"version"` //Version du fichier dont le texte est extrait
+	Content   string    `gorm:"type:text" json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Version d'un fichier ; le File correspondant reprend les champs de sa version courante
+type FileVersion struct {
+	ID             uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
+	FileID         uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_file_version" json:"file_id"`
+	Version        int       `gorm:"not null;uniqueIndex:idx_file_version" json:"version"`
+	FileName       string    `gorm:"type:varchar(100)" json:"file_name"`
+	FileType       string    `gorm:"type:varchar(100)" json:"file_type"`
+	MimeType       string    `gorm:"type:varchar(100)" json:"mime_type"`
+	Size           int64     `gorm:"type:bigint" json:"file_size"`
+	Checksum       string    `gorm:"type:varchar(64)" json:"checksum"`
+	StorageBackend string    `gorm:"type:varchar(20)" json:"-"`
+	StorageKey     string    `gorm:"type:varchar(255)" json:"-"`
+	UserID         uuid.UUID `gorm:"type:uuid" json:"user_id"` //Auteur de la version
+	PromotedFrom   *int      `json:"promoted_from,omitempty"`  //Version restaurée par cette version
+	CreatedAt      time.Time `json:"created_at"`
+	Current        bool      `gorm:"-" json:"current"`
+
+	ScanStatus    string     `gorm:"type:varchar(20);not null;default:pending;index" json:"scan_status"`
+	ScanSignature string     `gorm:"type:varchar(255)" json:"scan_signature,omitempty"`
+	ScannedAt     *time.Time `json:"scanned_at,omitempty"`
+}
+
+func (v *FileVersion) BeforeCreate(tx *gorm.DB) (err error) {
+	v.ID = uuid.New()
+	return
+}
+
+// Apply fait de cette version le contenu courant du fichier
+func (v FileVersion) Apply(f *File) {
+	f.Version = v.Version
+	f.FileName = v.FileName
+	f.FileType = v.FileType
+	f.MimeType = v.MimeType
+	f.Size = v.Size
+	f.Checksum = v.Checksum
+	f.StorageBackend = v.StorageBackend
+	f.StorageKey = v.StorageKey
+	f.ScanStatus = v.ScanStatus
+	f.ScanSignature = v.ScanSignature
+	f.ScannedAt = v.ScannedAt
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Project struct {
+	BaseModel
+	ID     uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
+	Name   string    `gorm:"type:varchar(100)" json:"name"`
+	UserID uuid.UUID `gorm:"type:uuid" json:"user_id"`                    //Propriétaire du projet
+	Tasks  []Task    `gorm:"foreignKey:ProjectID" json:"tasks,omitempty"` //Foreign key (taches)
+}
+
+func (p *Project) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+	return
+}
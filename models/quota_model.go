@@ -0,0 +1,15 @@
+package models
+
+import "github.com/google/uuid"
+
+// Quota de stockage propre à un utilisateur (prioritaire sur celui de son rôle)
+type UserQuota struct {
+	UserID     uuid.UUID `gorm:"type:uuid;primarykey" json:"user_id"`
+	QuotaBytes int64     `gorm:"type:bigint;not null" json:"quota_bytes"`
+}
+
+// Quota de stockage des utilisateurs d'un rôle
+type RoleQuota struct {
+	Role       string `gorm:"type:varchar(100);primarykey" json:"role"`
+	QuotaBytes int64  `gorm:"type:bigint;not null" json:"quota_bytes"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rapport généré en arrière-plan (trop de lignes pour une réponse directe). Status reprend
+// ProcessingPending / ProcessingReady / ProcessingFailed ; le fichier est supprimé à ExpiresAt
+type Report struct {
+	BaseModel
+	ID             uuid.UUID  `gorm:"type:uuid;primarykey" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;index" json:"user_id"` //Demandeur
+	Kind           string     `gorm:"type:varchar(20)" json:"kind"`   //overview, tasks ou stats
+	Format         string     `gorm:"type:varchar(10)" json:"format"`
+	Query          string     `gorm:"type:text" json:"query"` //Paramètres de la demande (filter, sort), rejoués à la génération
+	Status         string     `gorm:"type:varchar(20);index" json:"status"`
+	Error          string     `gorm:"type:text" json:"error,omitempty"`
+	FileName       string     `gorm:"type:varchar(255)" json:"file_name"`
+	Rows           int        `json:"rows"`
+	Size           int64      `json:"size"`
+	StorageBackend string     `gorm:"type:varchar(20)" json:"-"`
+	StorageKey     string     `gorm:"type:varchar(255)" json:"-"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	ExpiresAt      time.Time  `gorm:"index" json:"expires_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Types de ressources partageables
+const (
+	ResourceFile = "file"
+	ResourceTask = "task"
+)
+
+// Droits accordés par un partage
+const (
+	PermissionRead = "read"
+	PermissionEdit = "edit"
+)
+
+// Partage d'un fichier ou d'une tâche avec un autre utilisateur
+type Share struct {
+	ResourceType string    `gorm:"type:varchar(10);primarykey" json:"resource_type"` //file ou task
+	ResourceID   uuid.UUID `gorm:"type:uuid;primarykey" json:"resource_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;primarykey;index" json:"user_id"`   //Destinataire du partage
+	OwnerID      uuid.UUID `gorm:"type:uuid;index" json:"owner_id"`             //Propriétaire de la ressource
+	Permission   string    `gorm:"type:varchar(10);not null" json:"permission"` //read ou edit
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Lien public en lecture seule vers un fichier ou une tâche, utilisable sans compte
+type PublicLink struct {
+	BaseModel
+	ID           uuid.UUID  `gorm:"type:uuid;primarykey" json:"id"`
+	Token        string     `gorm:"type:varchar(64);uniqueIndex" json:"-"` //Partie secrète de l'URL
+	ResourceType string     `gorm:"type:varchar(10);index:idx_public_link_resource" json:"resource_type"`
+	ResourceID   uuid.UUID  `gorm:"type:uuid;index:idx_public_link_resource" json:"resource_id"`
+	OwnerID      uuid.UUID  `gorm:"type:uuid;index" json:"owner_id"` //Propriétaire de la ressource
+	CreatedBy    uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	PasswordHash string     `gorm:"type:varchar(100)" json:"-"` //bcrypt, vide si le lien n'est pas protégé
+	ExpiresAt    *time.Time `json:"expires_at"`                 //null : pas d'expiration
+	RevokedAt    *time.Time `json:"revoked_at"`
+	Views        int64      `gorm:"not null;default:0" json:"views"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+
+	FailedAttempts int        `gorm:"not null;default:0" json:"-"` //Mots de passe incorrects depuis le dernier succès
+	LockedUntil    *time.Time `json:"-"`                           //Mot de passe refusé jusqu'à cette date
+}
+
+func (l *PublicLink) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.New()
+	return
+}
+
+// Un lien est utilisable tant qu'il n'est ni révoqué ni expiré
+func (l PublicLink) Active(now time.Time) bool {
+	return l.RevokedAt == nil && (l.ExpiresAt == nil || now.Before(*l.ExpiresAt))
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Compteurs de tâches par utilisateur (tâches non supprimées), tenus à jour par un trigger
+// PostgreSQL à chaque création, modification ou suppression de tâche (database.MigrateTaskStats)
+type UserTaskStats struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primarykey" json:"user_id"`
+	Total     int64     `gorm:"not null;default:0" json:"total"`
+	Completed int64     `gorm:"not null;default:0" json:"completed"`
+	UpdatedAt time.Time `json:"updated_at"` //Dernière modification d'une tâche de l'utilisateur
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Tag struct {
+	ID   uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
+	Name string    `gorm:"type:varchar(50);uniqueIndex" json:"name"`
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	return
+}
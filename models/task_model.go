@@ -1,23 +1,44 @@
-package models
-
-import (
-	"time"
-
-	"github.com/google/uuid"
-	"gorm.io/gorm"
-)
-
-type Task struct {
-	BaseModel
-	ID          uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
-	Title       string    `gorm:"type:varchar(100)" json:"title"`
-	Description string    `gorm:"type:varchar(100)" json:"description"`
-	Completed   bool      `gorm:"type:bool" json:"completed"`
-	CreatedAT   time.Time `gorm:"type:date" json:"created_at"`
-	UserID      uuid.UUID `gorm:"type:uuid" json:"user_id"`
-}
-
-func (t *Task) BeforeCreate(tx *gorm.DB) (err error) {
-	t.ID = uuid.New()
-	return
-}
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Task struct {
+	BaseModel
+	ID          uuid.UUID  `gorm:"type:uuid;primarykey" json:"id"`
+	Title       string     `gorm:"type:varchar(100)" json:"title"`
+	Description string     `gorm:"type:varchar(100)" json:"description"`
+	Completed   bool       `gorm:"type:bool" json:"completed"`
+	CompletedAt *time.Time `gorm:"index" json:"completed_at"` //Date de complétion (null si la tâche est ouverte)
+	DueDate     *time.Time `json:"due_date"`                  //Echéance (optionnelle)
+	CreatedAT   time.Time  `gorm:"type:timestamptz;autoCreateTime;index" json:"created_at"`
+	UserID      uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	ProjectID   *uuid.UUID `gorm:"type:uuid;index" json:"project_id"`          //Projet (optionnel)
+	Tags        []Tag      `gorm:"many2many:task_tags;" json:"tags,omitempty"` //Les étiquettes
+}
+
+func (t *Task) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	return
+}
+
+// completed_at suit completed lors des créations et des Save : renseignée quand la tâche
+// est complétée, effacée à la réouverture (les Update d'une colonne la passent explicitement)
+func (t *Task) BeforeSave(tx *gorm.DB) (err error) {
+	if !t.Completed {
+		t.CompletedAt = nil
+	} else if t.CompletedAt == nil {
+		now := time.Now()
+		t.CompletedAt = &now
+	}
+	return
+}
+
+// Clé de pagination par curseur (created_at, id)
+func (t Task) CursorKey() (time.Time, uuid.UUID) {
+	return t.CreatedAT, t.ID
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Upload en plusieurs morceaux (protocole tus) ; un File est créé quand tout est reçu
+type Upload struct {
+	BaseModel
+	ID           uuid.UUID  `gorm:"type:uuid;primarykey" json:"id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	FileName     string     `gorm:"type:varchar(100)" json:"file_name"`
+	Metadata     string     `gorm:"type:text" json:"metadata"`        //En-tête Upload-Metadata d'origine
+	UploadLength int64      `gorm:"type:bigint" json:"upload_length"` //Taille totale annoncée
+	UploadOffset int64      `gorm:"type:bigint" json:"upload_offset"` //Octets déjà reçus
+	ExpiresAt    time.Time  `gorm:"index" json:"expires_at"`
+	FileID       *uuid.UUID `gorm:"type:uuid" json:"file_id"` //Renseigné une fois l'upload terminé
+	LockToken    *uuid.UUID `gorm:"type:uuid" json:"-"`       //PATCH en cours, sans transaction ouverte pendant l'envoi
+	LockedUntil  *time.Time `json:"-"`                        //Expiration du verrou, prolongée tant que l'envoi continue
+}
+
+func (u *Upload) BeforeCreate(tx *gorm.DB) (err error) {
+	u.ID = uuid.New()
+	return
+}
@@ -1,32 +1,41 @@
-package models
-
-import (
-	"time"
-
-	"github.com/golang-jwt/jwt/v4"
-	"github.com/google/uuid"
-)
-
-type User struct {
-	BaseModel
-	ID        uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
-	Nom       string    `gorm:"type:varchar(100)" json:"nom"`
-	Prenom    string    `gorm:"type:varchar(100)" json:"prenom"`
-	Email     string    `gorm:"type:varchar(100)" json:"email"`
-	DateNaiss time.Time `gorm:"type:date" json:"date_naissance"`
-	Genre     string    `gorm:"type:varchar(100)" json:"genre"`
-	Role      string    `gorm:"type:varchar(100)" json:"role"`
-	Tasks     []Task    `gorm:"foreignKey:UserID" json:"tasks,omitempty"` //Foreign key (taches)
-	Files     []File    `gorm:"foreignKey:UserID" json:"files,omitempty"` //Foreign key (fichiers)
-	Password  string    `gorm:"type:varchar(100)" json:"password"`
-}
-
-type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	jwt.RegisteredClaims
-}
-
-// func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
-// 	u.ID = uuid.New()
-// 	return
-// }
+package models
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+type User struct {
+	BaseModel
+	ID        uuid.UUID `gorm:"type:uuid;primarykey" json:"id"`
+	Nom       string    `gorm:"type:varchar(100)" json:"nom"`
+	Prenom    string    `gorm:"type:varchar(100)" json:"prenom"`
+	Email     string    `gorm:"type:varchar(100)" json:"email"`
+	DateNaiss time.Time `gorm:"type:date" json:"date_naissance"`
+	Genre     string    `gorm:"type:varchar(100)" json:"genre"`
+	Role      string    `gorm:"type:varchar(100)" json:"role"`
+	Timezone  string    `gorm:"type:varchar(64)" json:"timezone"`    //Fuseau IANA (ex. Europe/Paris) des statistiques, UTC si vide
+	Team      string    `gorm:"type:varchar(100);index" json:"team"` //Equipe, pour les moyennes par équipe
+	//L'utilisateur n'apparaît pas dans les classements et est anonymisé dans le résumé anonyme
+	LeaderboardOptOut bool   `gorm:"not null;default:false" json:"leaderboard_opt_out"`
+	Tasks             []Task `gorm:"foreignKey:UserID" json:"tasks,omitempty"` //Foreign key (taches)
+	Files             []File `gorm:"foreignKey:UserID" json:"files,omitempty"` //Foreign key (fichiers)
+	Password          string `gorm:"type:varchar(100)" json:"password"`
+}
+
+type Claims struct {
+	UserID uuid.UUID `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
+// 	u.ID = uuid.New()
+// 	return
+// }
+
+// Clé de pagination par curseur (created_at, id)
+func (u User) CursorKey() (time.Time, uuid.UUID) {
+	return u.CreatedAt, u.ID
+}
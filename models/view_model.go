@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Requête de tâches enregistrée (vue). Filter et Sort utilisent la même
+// syntaxe que les paramètres ?filter= et ?sort= des listes de tâches
+type SavedView struct {
+	BaseModel
+	ID      uuid.UUID        `gorm:"type:uuid;primarykey" json:"id"`
+	Key     string           `gorm:"-" json:"key,omitempty"` //Clé des listes intelligentes (today, overdue...)
+	Name    string           `gorm:"type:varchar(100)" json:"name"`
+	Filter  string           `gorm:"type:text" json:"filter"`
+	Sort    string           `gorm:"type:varchar(255)" json:"sort"`
+	GroupBy string           `gorm:"type:varchar(50)" json:"group_by"`
+	UserID  uuid.UUID        `gorm:"type:uuid;index" json:"user_id"`                 //Propriétaire de la vue
+	Shares  []SavedViewShare `gorm:"foreignKey:SavedViewID" json:"shares,omitempty"` //Partage avec les coéquipiers
+	BuiltIn bool             `gorm:"-" json:"built_in"`
+}
+
+type SavedViewShare struct {
+	SavedViewID uuid.UUID `gorm:"type:uuid;primarykey" json:"saved_view_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;primarykey" json:"user_id"`
+}
+
+func (v *SavedView) BeforeCreate(tx *gorm.DB) (err error) {
+	v.ID = uuid.New()
+	return
+}
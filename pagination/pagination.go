@@ -0,0 +1,225 @@
+// Pagination commune des endpoints de liste.
+//
+// Deux modes :
+//   - offset : ?page=2&limit=20 (mode historique)
+//   - keyset : ?cursor=&limit=20 puis ?cursor=<next_cursor> ; le curseur opaque
+//     encode (created_at, id) du dernier élément, les pages restent donc
+//     stables même si des lignes sont insérées entre deux appels.
+//
+// Avec ?total=true la réponse contient aussi le nombre total d'éléments.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// Keyed est implémenté par les modèles paginables par curseur
+type Keyed interface {
+	CursorKey() (time.Time, uuid.UUID)
+}
+
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (cur Cursor) Encode() string {
+	b, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cur Cursor
+	if err := json.Unmarshal(b, &cur); err != nil {
+		return nil, err
+	}
+	return &cur, nil
+}
+
+type Params struct {
+	Limit     int
+	Page      int     //Mode offset
+	Keyset    bool    //Mode curseur
+	Cursor    *Cursor //nil pour la première page en mode curseur
+	WithTotal bool
+}
+
+// ParamError est renvoyé dans le champ "error" de la réponse, d'où les tags JSON
+type ParamError struct {
+	Param  string `json:"param"`
+	Reason string `json:"reason"`
+}
+
+func (e *ParamError) Error() string {
+	return e.Param + ": " + e.Reason
+}
+
+// Envelope est la forme commune des réponses paginées
+type Envelope struct {
+	Items      any    `json:"items"`
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// FromContext lit limit, page, cursor et total depuis la requête
+func FromContext(c *gin.Context) (Params, error) {
+	p := Params{Limit: DefaultLimit, Page: 1}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return p, &ParamError{Param: "limit", Reason: "doit être un entier positif"}
+		}
+		p.Limit = min(limit, MaxLimit)
+	}
+
+	if v, ok := c.GetQuery("cursor"); ok {
+		if c.Query("page") != "" {
+			return p, &ParamError{Param: "cursor", Reason: "ne peut pas être utilisé avec page"}
+		}
+		p.Keyset = true
+		if v != "" {
+			cur, err := DecodeCursor(v)
+			if err != nil {
+				return p, &ParamError{Param: "cursor", Reason: "curseur invalide"}
+			}
+			p.Cursor = cur
+		}
+	} else if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return p, &ParamError{Param: "page", Reason: "doit être un entier positif"}
+		}
+		p.Page = page
+	}
+
+	p.WithTotal, _ = strconv.ParseBool(c.DefaultQuery("total", "false"))
+	return p, nil
+}
+
+// Find exécute la requête paginée sur db (filtres déjà appliqués) et ajoute l'en-tête Link.
+// Le tri (created_at, id) DESC est toujours ajouté en dernier pour garantir un ordre stable
+func Find[T Keyed](c *gin.Context, db *gorm.DB, table string, p Params) (*Envelope, error) {
+	db = db.Session(&gorm.Session{})
+	env := &Envelope{Limit: p.Limit}
+
+	if p.WithTotal {
+		var total int64
+		if err := db.Count(&total).Error; err != nil {
+			return nil, err
+		}
+		env.Total = &total
+	}
+
+	query := db.Order(table + ".created_at DESC").Order(table + ".id DESC").Limit(p.Limit + 1)
+	if p.Keyset {
+		if p.Cursor != nil {
+			query = query.Where("("+table+".created_at, "+table+".id) < (?, ?)", p.Cursor.CreatedAt, p.Cursor.ID)
+		}
+	} else {
+		env.Page = p.Page
+		query = query.Offset((p.Page - 1) * p.Limit)
+	}
+
+	//On lit un élément de plus que la limite pour savoir s'il reste une page
+	items := []T{}
+	if err := query.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) > p.Limit {
+		items = items[:p.Limit]
+		env.HasMore = true
+	}
+	env.Items = items
+
+	var links []string
+	if p.Keyset {
+		links = append(links, link(c, "first", "cursor", ""))
+		if env.HasMore {
+			createdAt, id := items[len(items)-1].CursorKey()
+			env.NextCursor = Cursor{CreatedAt: createdAt, ID: id}.Encode()
+			links = append(links, link(c, "next", "cursor", env.NextCursor))
+		}
+	} else {
+		links = offsetLinks(c, p, env)
+	}
+	c.Header("Link", strings.Join(links, ", "))
+
+	return env, nil
+}
+
+// FindOffset pagine en mode offset une requête déjà triée par l'appelant (agrégats,
+// classements) : le tri n'est pas complété et le mode curseur n'est pas disponible
+func FindOffset[T any](c *gin.Context, db *gorm.DB, p Params) (*Envelope, error) {
+	if p.Keyset {
+		return nil, &ParamError{Param: "cursor", Reason: "non disponible pour cette liste"}
+	}
+	db = db.Session(&gorm.Session{})
+	env := &Envelope{Limit: p.Limit, Page: p.Page}
+
+	if p.WithTotal {
+		var total int64
+		if err := db.Count(&total).Error; err != nil {
+			return nil, err
+		}
+		env.Total = &total
+	}
+
+	items := []T{}
+	if err := db.Limit(p.Limit + 1).Offset((p.Page - 1) * p.Limit).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) > p.Limit {
+		items = items[:p.Limit]
+		env.HasMore = true
+	}
+	env.Items = items
+
+	c.Header("Link", strings.Join(offsetLinks(c, p, env), ", "))
+	return env, nil
+}
+
+func offsetLinks(c *gin.Context, p Params, env *Envelope) []string {
+	links := []string{link(c, "first", "page", "1")}
+	if p.Page > 1 {
+		links = append(links, link(c, "prev", "page", strconv.Itoa(p.Page-1)))
+	}
+	if env.HasMore {
+		links = append(links, link(c, "next", "page", strconv.Itoa(p.Page+1)))
+	}
+	if env.Total != nil {
+		last := max(1, int((*env.Total+int64(p.Limit)-1)/int64(p.Limit)))
+		links = append(links, link(c, "last", "page", strconv.Itoa(last)))
+	}
+	return links
+}
+
+// Lien RFC 8288 vers la requête courante avec un paramètre modifié
+func link(c *gin.Context, rel, key, value string) string {
+	u := *c.Request.URL
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+}
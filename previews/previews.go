@@ -0,0 +1,176 @@
+// Génération des aperçus : vignettes d'images et première page des PDF, en PNG
+package previews
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type MIME des aperçus générés
+const ContentType = "image/png"
+
+var (
+	ErrUnsupported = errors.New("previews: type de fichier sans aperçu")
+	ErrTooLarge    = errors.New("previews: image trop grande")
+)
+
+var (
+	// Plus grand côté de l'aperçu en pixels (PREVIEW_SIZE)
+	Size = 320
+
+	// Nombre maximal de pixels d'une image source, pour borner la mémoire du décodage
+	MaxPixels = 40_000_000
+
+	// Taille maximale en octets d'une image source
+	MaxImageSize int64 = 50 << 20
+
+	// Durée maximale du rendu d'un PDF par pdftoppm (PREVIEW_TIMEOUT)
+	Timeout = time.Minute
+)
+
+// Load lit PREVIEW_SIZE et PREVIEW_TIMEOUT (durée Go, ex. 1m)
+func Load() error {
+	if value := os.Getenv("PREVIEW_SIZE"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 16 || n > 2048 {
+			return fmt.Errorf("previews: PREVIEW_SIZE %q invalide", value)
+		}
+		Size = n
+	}
+	if value := os.Getenv("PREVIEW_TIMEOUT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("previews: PREVIEW_TIMEOUT %q invalide", value)
+		}
+		Timeout = d
+	}
+	return nil
+}
+
+// Supported indique si un aperçu peut être généré pour ce type MIME
+func Supported(mimeType string) bool {
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/gif", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// Generate produit l'aperçu PNG du contenu r selon son type MIME
+func Generate(ctx context.Context, mimeType string, r io.Reader) ([]byte, error) {
+	switch {
+	case mimeType == "application/pdf":
+		return PDFFirstPage(ctx, r, Size)
+	case Supported(mimeType):
+		return Thumbnail(r, Size)
+	}
+	return nil, ErrUnsupported
+}
+
+// Thumbnail réduit une image JPEG, PNG ou GIF pour que son plus grand côté fasse au plus side pixels.
+// Seul l'en-tête est lu avant de vérifier les dimensions ; l'image n'est jamais chargée en entier en mémoire
+func Thumbnail(r io.Reader, side int) ([]byte, error) {
+	limited := &io.LimitedReader{R: r, N: MaxImageSize + 1}
+
+	//Dimensions lues dans l'en-tête, gardé pour le décodage complet
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(limited, &header))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	src, _, err := image.Decode(io.MultiReader(&header, limited))
+	if limited.N <= 0 {
+		return nil, ErrTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scale(src, side)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PDFFirstPage rend la première page d'un PDF en PNG avec pdftoppm (poppler-utils), arrêté après Timeout
+func PDFFirstPage(ctx context.Context, r io.Reader, side int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "preview-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.pdf")
+	f, err := os.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	output := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, "pdftoppm", "-png", "-f", "1", "-l", "1",
+		"-scale-to", strconv.Itoa(side), "-singlefile", input, output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return os.ReadFile(output + ".png")
+}
+
+// Réduction par moyenne des pixels de chaque zone source ; l'image n'est jamais agrandie
+func scale(src image.Image, side int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= side && h <= side {
+		return src
+	}
+	dw, dh := side, h*side/w
+	if h > w {
+		dw, dh = w*side/h, side
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			//Moyenne en couleurs prémultipliées, convertie en NRGBA
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,176 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"log"
+	"projet1/blobs"
+	"projet1/database"
+	"projet1/models"
+	"projet1/response"
+	"projet1/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Objet référencé par la base
+type objectRef struct {
+	backend, key string
+}
+
+// Reconcile compare la base et le stockage :
+//   - objets stockés qu'aucun fichier, version, blob, aperçu ou rapport ne référence (orphelins) ;
+//   - versions dont l'objet n'existe plus (manquants).
+//
+// Avec fix, les orphelins plus anciens que minAge sont supprimés (les plus récents peuvent
+// appartenir à un upload en cours), les blobs manquants sont marqués missing, les anciennes
+// versions sans contenu sont supprimées et les fichiers dont le contenu courant manque
+// passent dans la corbeille
+func Reconcile(ctx context.Context, fix bool, minAge time.Duration) (response.ReconcileReport, error) {
+	report := response.ReconcileReport{Orphans: []response.OrphanObject{}, Missing: []response.MissingObject{}, Fixed: fix}
+
+	refs, err := referencedObjects()
+	if err != nil {
+		return report, err
+	}
+
+	//Objets sans référence
+	cutoff := time.Now().Add(-minAge)
+	for name, backend := range storage.Backends {
+		err := backend.List(ctx, "", func(obj storage.ObjectInfo) error {
+			report.ObjectsScanned++
+			if refs[objectRef{name, obj.Key}] || obj.ModTime.After(cutoff) {
+				return nil
+			}
+			report.Orphans = append(report.Orphans, response.OrphanObject{
+				StorageBackend: name,
+				StorageKey:     obj.Key,
+				Size:           obj.Size,
+				ModTime:        obj.ModTime,
+			})
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+	if fix {
+		for _, orphan := range report.Orphans {
+			blobs.DeleteObjects(ctx, []models.Blob{{StorageBackend: orphan.StorageBackend, StorageKey: orphan.StorageKey}})
+		}
+	}
+
+	//Versions sans objet (fichiers dans la corbeille compris)
+	var versions []struct {
+		models.FileVersion
+		CurrentVersion int
+	}
+	err = database.DB.Model(&models.FileVersion{}).
+		Select("file_versions.*, files.version AS current_version").
+		Joins("JOIN files ON files.id = file_versions.file_id").
+		Order("file_versions.file_id, file_versions.version").Scan(&versions).Error
+	if err != nil {
+		return report, err
+	}
+	exists := map[objectRef]bool{}
+	for _, v := range versions {
+		ref := objectRef{backendName(v.StorageBackend), v.StorageKey}
+		found, checked := exists[ref]
+		if !checked {
+			found, err = objectExists(ctx, ref)
+			if err != nil {
+				return report, err
+			}
+			exists[ref] = found
+		}
+		if found {
+			continue
+		}
+
+		missing := response.MissingObject{
+			StorageBackend: ref.backend,
+			StorageKey:     ref.key,
+			FileID:         v.FileID,
+			Version:        v.Version,
+			Current:        v.Version == v.CurrentVersion,
+		}
+		if fix {
+			if missing.Action, err = fixMissing(v.FileVersion, missing.Current); err != nil {
+				return report, err
+			}
+		}
+		report.Missing = append(report.Missing, missing)
+	}
+	return report, nil
+}
+
+// Objets référencés par les fichiers (y compris dans la corbeille), les versions, les blobs et les aperçus
+func referencedObjects() (map[objectRef]bool, error) {
+	var rows []struct {
+		StorageBackend string
+		StorageKey     string
+	}
+	err := database.DB.Raw(`SELECT storage_backend, storage_key FROM files
+		UNION SELECT preview_backend, preview_key FROM files WHERE coalesce(preview_key, '') <> ''
+		UNION SELECT storage_backend, storage_key FROM file_versions
+		UNION SELECT storage_backend, storage_key FROM blobs
+		UNION SELECT storage_backend, storage_key FROM reports WHERE coalesce(storage_key, '') <> ''`).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[objectRef]bool, len(rows))
+	for _, row := range rows {
+		refs[objectRef{backendName(row.StorageBackend), row.StorageKey}] = true
+	}
+	return refs, nil
+}
+
+func objectExists(ctx context.Context, ref objectRef) (bool, error) {
+	if ref.key == "" {
+		return false, nil
+	}
+	backend, err := storage.Get(ref.backend)
+	if err != nil {
+		return false, err
+	}
+	_, err = backend.Stat(ctx, ref.key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Nettoie une version dont l'objet n'existe plus et renvoie l'action effectuée
+func fixMissing(v models.FileVersion, current bool) (string, error) {
+	if v.Checksum != "" {
+		err := database.DB.Model(&models.Blob{}).Where("hash = ?", v.Checksum).Update("status", models.BlobMissing).Error
+		if err != nil {
+			return "", err
+		}
+	}
+	if current {
+		res := database.DB.Delete(&models.File{}, "id = ?", v.FileID)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return "", res.Error
+		}
+		log.Printf("fichier %s: contenu introuvable, placé dans la corbeille", v.FileID)
+		return "trashed", nil
+	}
+	//Les caches de statistiques sont ceux du serveur, pas de ce processus : ils expirent d'eux-mêmes
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&v).Error; err != nil {
+			return err
+		}
+		_, err := blobs.Release(tx, v)
+		return err
+	})
+	return "version_deleted", err
+}
+
+func backendName(name string) string {
+	if name == "" {
+		return "local"
+	}
+	return name
+}
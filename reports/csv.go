@@ -0,0 +1,49 @@
+package reports
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// Un tableau après l'autre, chacun précédé de son titre s'il y en a plusieurs et séparés
+// par une ligne vide. Le BOM UTF-8 permet à Excel de lire les accents
+func renderCSV(w io.Writer, r Report) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	for i, t := range r.Tables {
+		if len(r.Tables) > 1 {
+			if i > 0 {
+				cw.Write(nil)
+			}
+			cw.Write([]string{t.Title})
+		}
+		cw.Write(t.Columns)
+		record := make([]string, len(t.Columns))
+		for _, row := range t.Rows {
+			for j := range record {
+				record[j] = ""
+				if j < len(row) {
+					record[j] = csvText(row[j])
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Un texte commençant par =, +, - ou @ serait lu comme une formule par un tableur : il est
+// préfixé d'une apostrophe (les nombres ne sont pas concernés)
+func csvText(v any) string {
+	s := text(v)
+	if _, ok := v.(string); ok && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
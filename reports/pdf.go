@@ -0,0 +1,242 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mise en page : A4 paysage, tableaux en Courier (chasse fixe : la largeur d'un texte est
+// son nombre de caractères), titres en Helvetica
+const (
+	pageWidth     = 842.0
+	pageHeight    = 595.0
+	pageMargin    = 36.0
+	cellFontSize  = 8.0
+	charWidth     = cellFontSize * 0.6 //Largeur d'un caractère Courier
+	lineHeight    = 11.0
+	maxCellChars  = 40
+	minCellChars  = 4
+	pdfFontsStart = 3 //Objets 1 et 2 : catalogue et arbre des pages
+)
+
+// Polices standard (pas d'intégration), encodées en WinAnsi
+var pdfFonts = []string{"Helvetica", "Helvetica-Bold", "Courier", "Courier-Bold"}
+
+// Caractères de WinAnsi (cp1252) hors Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// Ecriture du PDF au fil des pages : seuls le contenu de la page en cours et les positions
+// des objets (pour la table xref) restent en mémoire
+type pdfWriter struct {
+	w       io.Writer
+	offset  int64
+	err     error
+	offsets map[int]int64
+	next    int   //Prochain numéro d'objet libre
+	pages   []int //Objets page
+	title   string
+	content bytes.Buffer
+	y       float64 //Position verticale courante (depuis le bas)
+}
+
+func renderPDF(w io.Writer, r Report) error {
+	p := &pdfWriter{w: w, offsets: map[int]int64{}, next: pdfFontsStart + len(pdfFonts), title: r.Title}
+	p.write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	for i, font := range pdfFonts {
+		p.object(pdfFontsStart+i, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font))
+	}
+
+	p.newPage()
+	p.text("F2", 16, pageMargin, p.y-16, r.Title)
+	p.text("F1", 9, pageMargin, p.y-30, "Généré le "+r.GeneratedAt.Format("2006-01-02 15:04 MST"))
+	p.y -= 48
+	for _, t := range r.Tables {
+		p.table(t)
+	}
+	p.endPage()
+
+	kids := make([]string, len(p.pages))
+	for i, page := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	p.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	p.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	xref := p.offset
+	p.write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", p.next))
+	for i := 1; i < p.next; i++ {
+		p.write(fmt.Sprintf("%010d 00000 n \n", p.offsets[i]))
+	}
+	p.write(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.next, xref))
+	return p.err
+}
+
+// Un tableau : titre, en-tête (répété en haut de chaque nouvelle page) et lignes alternées
+func (p *pdfWriter) table(t Table) {
+	widths := columnWidths(t)
+	totalWidth := 0.0
+	for _, w := range widths {
+		totalWidth += float64(w+1) * charWidth
+	}
+
+	//Titre, en-tête et au moins une ligne sur la même page
+	if p.y-3*lineHeight-20 < pageMargin {
+		p.endPage()
+		p.newPage()
+	}
+	p.text("F2", 11, pageMargin, p.y-11, t.Title)
+	p.y -= 20
+	header := func() {
+		p.rect(0.85, pageMargin, p.y-lineHeight, totalWidth, lineHeight)
+		p.row("F4", widths, t.Columns)
+	}
+	header()
+
+	rows := t.Rows
+	if len(rows) == 0 {
+		rows = [][]any{{"Aucune donnée"}}
+	}
+	cells := make([]string, len(widths))
+	for i, row := range rows {
+		if p.y-lineHeight < pageMargin+lineHeight {
+			p.endPage()
+			p.newPage()
+			header()
+		}
+		for j := range cells {
+			cells[j] = ""
+			if j < len(row) {
+				cells[j] = text(row[j])
+			}
+		}
+		if i%2 == 1 {
+			p.rect(0.95, pageMargin, p.y-lineHeight, totalWidth, lineHeight)
+		}
+		p.row("F3", widths, cells)
+	}
+	p.y -= lineHeight
+}
+
+// Une ligne de cellules tronquées à la largeur de leur colonne
+func (p *pdfWriter) row(font string, widths []int, cells []string) {
+	x := pageMargin
+	for j, w := range widths {
+		if j < len(cells) {
+			p.text(font, cellFontSize, x+charWidth/2, p.y-lineHeight+3, truncateCell(cells[j], w))
+		}
+		x += float64(w+1) * charWidth
+	}
+	p.y -= lineHeight
+}
+
+// Largeur (en caractères) de chaque colonne : celle de son texte le plus long, puis les plus
+// larges sont réduites jusqu'à ce que le tableau tienne dans la page
+func columnWidths(t Table) []int {
+	widths := make([]int, len(t.Columns))
+	for j, col := range t.Columns {
+		widths[j] = min(max(utf8.RuneCountInString(col), minCellChars), maxCellChars)
+		for _, row := range t.Rows {
+			if j < len(row) {
+				widths[j] = min(max(widths[j], utf8.RuneCountInString(text(row[j]))), maxCellChars)
+			}
+		}
+	}
+
+	usable := pageWidth - 2*pageMargin
+	available := int(usable/charWidth) - len(widths) //Une espace entre les colonnes
+	for {
+		total, widest := 0, 0
+		for j, w := range widths {
+			total += w
+			if w > widths[widest] {
+				widest = j
+			}
+		}
+		if total <= available || widths[widest] <= minCellChars {
+			return widths
+		}
+		widths[widest]--
+	}
+}
+
+func truncateCell(s string, width int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+func (p *pdfWriter) newPage() {
+	p.content.Reset()
+	p.y = pageHeight - pageMargin
+}
+
+// Termine la page : pied de page, flux de contenu et objet page
+func (p *pdfWriter) endPage() {
+	p.text("F1", 8, pageMargin, pageMargin/2, fmt.Sprintf("%s - page %d", p.title, len(p.pages)+1))
+
+	contentObj, pageObj := p.next, p.next+1
+	p.next += 2
+	p.object(contentObj, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", p.content.Len(), p.content.String()))
+
+	fonts := make([]string, len(pdfFonts))
+	for i := range pdfFonts {
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, pdfFontsStart+i)
+	}
+	p.object(pageObj, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Contents %d 0 R /Resources << /Font << %s >> >> >>",
+		pageWidth, pageHeight, contentObj, strings.Join(fonts, " ")))
+	p.pages = append(p.pages, pageObj)
+}
+
+func (p *pdfWriter) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %g Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// Rectangle plein en niveau de gris (0 noir, 1 blanc)
+func (p *pdfWriter) rect(gray, x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%g g %.2f %.2f %.2f %.2f re f 0 g\n", gray, x, y, w, h)
+}
+
+func (p *pdfWriter) object(num int, body string) {
+	p.offsets[num] = p.offset
+	p.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", num, body))
+}
+
+func (p *pdfWriter) write(s string) {
+	if p.err != nil {
+		return
+	}
+	n, err := io.WriteString(p.w, s)
+	p.offset += int64(n)
+	p.err = err
+}
+
+// Chaîne PDF littérale en WinAnsi : caractères non représentables remplacés par "?"
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x7F || (r >= 0xA0 && r <= 0xFF):
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			b.WriteByte(winAnsi[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
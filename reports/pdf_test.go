@@ -0,0 +1,91 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Rapport", "Rapport"},
+		{"(a) \\ b", "\\(a\\) \\\\ b"},
+		{"été à l'œil", "\xe9t\xe9 \xe0 l'\x9cil"}, //Latin-1 et WinAnsi
+		{"Prix : 10 €", "Prix : 10 \x80"},
+		{"“guillemets” – tiret", "\x93guillemets\x94 \x96 tiret"},
+		{"ligne\nsuivante\ttab", "ligne suivante tab"},
+		{"日本", "??"},
+		{"😀", "?"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := pdfString(tt.in); got != tt.want {
+			t.Errorf("pdfString(%q) = %q, attendu %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// La structure du PDF est cohérente : en-tête, table xref dont chaque entrée pointe sur son
+// objet, startxref sur la table, et une page de plus quand les lignes ne tiennent pas sur une
+func TestRenderPDF(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      int
+		wantPages int
+	}{
+		{"tableau vide", 0, 1},
+		{"une page", 10, 1},
+		{"plusieurs pages", 200, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := make([][]any, tt.rows)
+			for i := range rows {
+				rows[i] = []any{fmt.Sprintf("tâche (%d)", i), i%2 == 0, float64(i) / 3}
+			}
+			r := Report{Title: "Rapport d'activité", GeneratedAt: time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC), Tables: []Table{
+				{Title: "Tâches", Columns: []string{"Titre", "Terminée", "Taux"}, Rows: rows},
+			}}
+			var buf bytes.Buffer
+			if err := Render(&buf, PDF, r); err != nil {
+				t.Fatal(err)
+			}
+			pdf := buf.Bytes()
+			if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+				t.Fatal("en-tête ou fin de fichier PDF manquant")
+			}
+
+			m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+			if m == nil {
+				t.Fatal("startxref manquant")
+			}
+			xref, _ := strconv.Atoi(string(m[1]))
+			if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+				t.Fatalf("startxref %d ne pointe pas sur la table xref", xref)
+			}
+			var first, count int
+			fmt.Sscanf(string(pdf[xref:]), "xref\n%d %d\n", &first, &count)
+			entries := strings.Split(string(pdf[xref:]), "\n")[3 : 3+count-1]
+			for i, entry := range entries {
+				offset, _ := strconv.Atoi(entry[:10])
+				if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+					t.Errorf("entrée xref %d : %q à l'octet %d", i+1, pdf[offset:offset+12], offset)
+				}
+			}
+
+			if got := bytes.Count(pdf, []byte("/Type /Page ")); got != tt.wantPages {
+				t.Errorf("%d page(s), attendu %d", got, tt.wantPages)
+			}
+			if !bytes.Contains(pdf, []byte(fmt.Sprintf("/Count %d", tt.wantPages))) {
+				t.Errorf("/Count %d manquant dans l'arbre des pages", tt.wantPages)
+			}
+		})
+	}
+}
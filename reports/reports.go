@@ -0,0 +1,143 @@
+// Rendu des rapports (tableaux de données) en CSV, XLSX ou PDF, sans dépendance :
+// le XLSX est un zip de XML SpreadsheetML, le PDF n'utilise que les polices standard
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats de rendu
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+	PDF  = "pdf"
+)
+
+// ContentTypes donne le type MIME de chaque format
+var ContentTypes = map[string]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	PDF:  "application/pdf",
+}
+
+var ErrUnsupportedFormat = errors.New("reports: format non supporté (csv, xlsx ou pdf)")
+
+// Report est un titre et une suite de tableaux (une feuille par tableau en XLSX)
+type Report struct {
+	Title       string
+	GeneratedAt time.Time
+	Tables      []Table
+}
+
+// Table : les valeurs des lignes sont des string, int, int64, float64, bool, time.Time,
+// *time.Time (vide si nil) ou fmt.Stringer (ex. uuid.UUID)
+type Table struct {
+	Title   string
+	Columns []string
+	Rows    [][]any
+}
+
+// Rows compte les lignes de tous les tableaux
+func (r Report) Rows() int {
+	n := 0
+	for _, t := range r.Tables {
+		n += len(t.Rows)
+	}
+	return n
+}
+
+// Negotiate choisit le format : le paramètre format s'il est donné, sinon le premier type
+// supporté de l'en-tête Accept (dans l'ordre de préférence q), CSV si Accept est vide ou */*
+func Negotiate(format, accept string) (string, error) {
+	if format != "" {
+		format = strings.ToLower(format)
+		if _, ok := ContentTypes[format]; !ok {
+			return "", ErrUnsupportedFormat
+		}
+		return format, nil
+	}
+	if strings.TrimSpace(accept) == "" {
+		return CSV, nil
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		candidate := ""
+		switch mediaType {
+		case "text/csv":
+			candidate = CSV
+		case ContentTypes[XLSX]:
+			candidate = XLSX
+		case "application/pdf":
+			candidate = PDF
+		case "*/*", "text/*":
+			candidate = CSV
+		}
+		if candidate != "" && q > bestQ {
+			best, bestQ = candidate, q
+		}
+	}
+	if best == "" {
+		return "", ErrUnsupportedFormat
+	}
+	return best, nil
+}
+
+// Render écrit le rapport dans le format demandé
+func Render(w io.Writer, format string, r Report) error {
+	switch format {
+	case CSV:
+		return renderCSV(w, r)
+	case XLSX:
+		return renderXLSX(w, r)
+	case PDF:
+		return renderPDF(w, r)
+	}
+	return ErrUnsupportedFormat
+}
+
+// Texte d'une valeur, pour le CSV et le PDF
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		if v {
+			return "oui"
+		}
+		return "non"
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02 15:04")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return text(*v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,143 @@
+package reports
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{"paramètre format", "xlsx", "", XLSX, false},
+		{"paramètre en majuscules", "PDF", "", PDF, false},
+		{"paramètre prioritaire sur Accept", "csv", "application/pdf", CSV, false},
+		{"paramètre inconnu", "docx", "", "", true},
+		{"Accept vide", "", "", CSV, false},
+		{"Accept */*", "", "*/*", CSV, false},
+		{"Accept text/*", "", "text/*", CSV, false},
+		{"Accept PDF", "", "application/pdf", PDF, false},
+		{"Accept XLSX", "", ContentTypes[XLSX], XLSX, false},
+		{"CSV avec charset", "", "text/csv; charset=utf-8", CSV, false},
+		{"préférence q", "", "text/csv;q=0.5, application/pdf;q=0.9", PDF, false},
+		{"égalité : le premier gagne", "", "application/pdf, text/csv", PDF, false},
+		{"types non supportés ignorés", "", "text/html, application/json;q=0.9, text/csv;q=0.1", CSV, false},
+		{"q invalide ignoré", "", "application/pdf;q=abc, text/csv;q=0.2", CSV, false},
+		{"q=0 refusé", "", "application/pdf;q=0", "", true},
+		{"aucun type supporté", "", "text/html", "", true},
+		{"Accept mal formé", "", ";;;", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Negotiate(tt.format, tt.accept)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedFormat) {
+					t.Errorf("Negotiate(%q, %q) = %q, %v ; attendu ErrUnsupportedFormat", tt.format, tt.accept, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Negotiate(%q, %q) = %q, %v ; attendu %q", tt.format, tt.accept, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	date := time.Date(2024, 5, 17, 9, 5, 0, 0, time.UTC)
+	id := uuid.MustParse("6f1c2a3b-4d5e-4f60-8a9b-0c1d2e3f4a5b")
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil", nil, ""},
+		{"texte", "bonjour", "bonjour"},
+		{"vrai", true, "oui"},
+		{"faux", false, "non"},
+		{"entier", 42, "42"},
+		{"int64", int64(-7), "-7"},
+		{"décimal", 66.666, "66.67"},
+		{"date", date, "2024-05-17 09:05"},
+		{"date nulle", time.Time{}, ""},
+		{"pointeur de date", &date, "2024-05-17 09:05"},
+		{"pointeur nil", (*time.Time)(nil), ""},
+		{"Stringer", id, id.String()},
+	}
+	for _, tt := range tests {
+		if got := text(tt.value); got != tt.want {
+			t.Errorf("%s : text(%v) = %q, attendu %q", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"rapport", "rapport"},
+		{"=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{"+33 6 12", "'+33 6 12"},
+		{"-2+3", "'-2+3"},
+		{"@cmd", "'@cmd"},
+		{"\tcaché", "'\tcaché"},
+		{"\rcaché", "'\rcaché"},
+		{"a=b", "a=b"},
+		{"", ""},
+		{-5, "-5"}, //Un nombre négatif n'est pas une formule
+		{-1.5, "-1.50"},
+	}
+	for _, tt := range tests {
+		if got := csvText(tt.value); got != tt.want {
+			t.Errorf("csvText(%#v) = %q, attendu %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		tables []Table
+		want   string
+	}{
+		{"un tableau", []Table{
+			{Title: "Tâches", Columns: []string{"Titre", "Terminée"}, Rows: [][]any{{"écrire, relire", true}, {"=relire", false}}},
+		}, "Titre,Terminée\n\"écrire, relire\",oui\n'=relire,non\n"},
+		//Titre avant chaque tableau, une ligne vide entre eux, cellules manquantes vides
+		{"plusieurs tableaux", []Table{
+			{Title: "A", Columns: []string{"x"}, Rows: [][]any{{1}}},
+			{Title: "B", Columns: []string{"y", "z"}, Rows: [][]any{{"court"}}},
+		}, "A\nx\n1\n\nB\ny,z\ncourt,\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, CSV, Report{Title: "Test", Tables: tt.tables}); err != nil {
+				t.Fatal(err)
+			}
+			got, ok := strings.CutPrefix(buf.String(), "\uFEFF")
+			if !ok {
+				t.Error("BOM UTF-8 manquant")
+			}
+			if got != tt.want {
+				t.Errorf("CSV =\n%q\nattendu\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, "docx", Report{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Render(docx) = %v, attendu ErrUnsupportedFormat", err)
+	}
+}
@@ -0,0 +1,190 @@
+package reports
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+// Largeur maximale d'une colonne (en caractères)
+const maxColumnWidth = 60
+
+// Styles : 0 normal, 1 en-tête en gras, 2 nombre à deux décimales
+const xlsxStyles = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles></styleSheet>`
+
+// Classeur avec une feuille par tableau, la ligne d'en-tête figée
+func renderXLSX(w io.Writer, r Report) error {
+	zw := zip.NewWriter(w)
+	names := sheetNames(r.Tables)
+
+	var types, sheets, rels strings.Builder
+	for i, name := range names {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(name), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(names)+1)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			types.String() + `</Types>`},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			rels.String() + `</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	for i, t := range r.Tables {
+		f, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeSheet(f, t); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeSheet(w io.Writer, t Table) error {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	//Largeur de chaque colonne d'après son texte le plus long
+	b.WriteString(`<cols>`)
+	for j, col := range t.Columns {
+		width := utf8.RuneCountInString(col)
+		for _, row := range t.Rows {
+			if j < len(row) {
+				width = max(width, utf8.RuneCountInString(text(row[j])))
+			}
+		}
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, j+1, j+1, min(width+2, maxColumnWidth))
+	}
+	b.WriteString(`</cols><sheetData>`)
+
+	b.WriteString(`<row r="1">`)
+	for j, col := range t.Columns {
+		fmt.Fprintf(&b, `<c r="%s1" t="inlineStr" s="1"><is><t>%s</t></is></c>`, columnName(j), escape(col))
+	}
+	b.WriteString(`</row>`)
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+
+	//Les lignes sont écrites au fur et à mesure
+	for i, row := range t.Rows {
+		b.Reset()
+		fmt.Fprintf(&b, `<row r="%d">`, i+2)
+		for j, v := range row {
+			writeCell(&b, fmt.Sprintf("%s%d", columnName(j), i+2), v)
+		}
+		b.WriteString(`</row>`)
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, `</sheetData></worksheet>`)
+	return err
+}
+
+// Nombres et booléens gardent leur type pour que le tableur puisse calculer ; le reste est du texte
+func writeCell(b *strings.Builder, ref string, v any) {
+	switch v := v.(type) {
+	case int:
+		fmt.Fprintf(b, `<c r="%s"><v>%d</v></c>`, ref, v)
+	case int64:
+		fmt.Fprintf(b, `<c r="%s"><v>%d</v></c>`, ref, v)
+	case float64:
+		fmt.Fprintf(b, `<c r="%s" s="2"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		value := 0
+		if v {
+			value = 1
+		}
+		fmt.Fprintf(b, `<c r="%s" t="b"><v>%d</v></c>`, ref, value)
+	default:
+		if s := text(v); s != "" {
+			fmt.Fprintf(b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escape(s))
+		}
+	}
+}
+
+// Nom de colonne d'un indice : 0 -> A, 25 -> Z, 26 -> AA
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// Noms de feuilles valides : 31 caractères au plus, sans []:*?/\, uniques
+func sheetNames(tables []Table) []string {
+	names := make([]string, len(tables))
+	used := map[string]bool{}
+	for i, t := range tables {
+		base := strings.TrimSpace(strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return ' '
+			}
+			return r
+		}, t.Title))
+		if base == "" {
+			base = fmt.Sprintf("Feuille %d", i+1)
+		}
+		name := truncateRunes(base, 31)
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncateRunes(base, 31-len(suffix)) + suffix
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// Echappement XML ; les caractères interdits en XML sont remplacés
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
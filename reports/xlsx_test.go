@@ -0,0 +1,141 @@
+package reports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+		{16383, "XFD"}, //Dernière colonne d'Excel
+	}
+	for _, tt := range tests {
+		if got := columnName(tt.index); got != tt.want {
+			t.Errorf("columnName(%d) = %q, attendu %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestSheetNames(t *testing.T) {
+	long := strings.Repeat("é", 40)
+	tests := []struct {
+		name   string
+		titles []string
+		want   []string
+	}{
+		{"noms simples", []string{"Tâches", "Projets"}, []string{"Tâches", "Projets"}},
+		{"caractères interdits", []string{"a/b:c*d?[e]\\f"}, []string{"a b c d  e  f"}},
+		{"titre vide", []string{"", " / "}, []string{"Feuille 1", "Feuille 2"}},
+		{"doublons sans tenir compte de la casse", []string{"Stats", "stats", "STATS"}, []string{"Stats", "stats (2)", "STATS (3)"}},
+		{"31 caractères au plus", []string{long}, []string{strings.Repeat("é", 31)}},
+		{"doublon tronqué avec son suffixe", []string{long, long}, []string{strings.Repeat("é", 31), strings.Repeat("é", 27) + " (2)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables := make([]Table, len(tt.titles))
+			for i, title := range tt.titles {
+				tables[i].Title = title
+			}
+			got := sheetNames(tables)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sheetNames = %q, attendu %q", got, tt.want)
+			}
+			for _, name := range got {
+				if utf8.RuneCountInString(name) > 31 {
+					t.Errorf("%q dépasse 31 caractères", name)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCell(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"entier", 3, `<c r="B2"><v>3</v></c>`},
+		{"int64", int64(12), `<c r="B2"><v>12</v></c>`},
+		{"décimal", 66.5, `<c r="B2" s="2"><v>66.5</v></c>`},
+		{"booléen", true, `<c r="B2" t="b"><v>1</v></c>`},
+		{"texte échappé", "a < b & c", `<c r="B2" t="inlineStr"><is><t xml:space="preserve">a &lt; b &amp; c</t></is></c>`},
+		{"vide", "", ``},
+		{"nil", nil, ``},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		writeCell(&b, "B2", tt.value)
+		if got := b.String(); got != tt.want {
+			t.Errorf("%s : writeCell = %s, attendu %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Le classeur est un zip dont chaque partie est du XML bien formé, avec une feuille par tableau
+func TestRenderXLSX(t *testing.T) {
+	r := Report{Title: "Test", Tables: []Table{
+		{Title: "Tâches", Columns: []string{"Titre", "Taux"}, Rows: [][]any{{"<script>", 50.0}, {"b", nil}}},
+		{Title: "Tâches", Columns: []string{"x"}},
+	}}
+	var buf bytes.Buffer
+	if err := Render(&buf, XLSX, r); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(content)
+
+		d := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := d.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s : XML invalide : %v", f.Name, err)
+			}
+		}
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("partie %s manquante", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Tâches (2)"`) {
+		t.Error("le second tableau de même titre doit avoir un nom de feuille distinct")
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet1.xml"], "&lt;script&gt;") {
+		t.Error("le texte des cellules doit être échappé")
+	}
+}
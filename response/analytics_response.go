@@ -0,0 +1,22 @@
+package response
+
+// Série temporelle des tâches, prête pour un graphique : Labels donne le début de chaque
+// intervalle et chaque série a une valeur par intervalle (0 si aucune tâche)
+type TaskAnalytics struct {
+	Interval string            `json:"interval"` //day, week ou month
+	Timezone string            `json:"timezone"` //Fuseau IANA dans lequel les intervalles sont découpés
+	From     string            `json:"from"`     //Début du premier intervalle (YYYY-MM-DD)
+	To       string            `json:"to"`       //Dernier jour inclus (YYYY-MM-DD)
+	GroupBy  string            `json:"group_by"` //none, user ou project
+	Labels   []string          `json:"labels"`
+	Series   []AnalyticsSeries `json:"series"`
+}
+
+type AnalyticsSeries struct {
+	Key            string  `json:"key"`   //ID de l'utilisateur ou du projet, "all" sans regroupement, "none" pour les tâches sans projet
+	Label          string  `json:"label"` //Nom affichable
+	Created        []int64 `json:"created"`
+	Completed      []int64 `json:"completed"`
+	TotalCreated   int64   `json:"total_created"`
+	TotalCompleted int64   `json:"total_completed"`
+}
@@ -0,0 +1,92 @@
+package response
+
+import (
+	"projet1/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DownloadLink struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"` //Lien à utiliser sans token jusqu'à expires_at
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Rapport de la dernière vérification des fichiers stockés
+type BlobReport struct {
+	Running    bool          `json:"running"`
+	StartedAt  *time.Time    `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at"`
+	Checked    int           `json:"checked"`    //Blobs relus et comparés à leur SHA-256
+	Backfilled int           `json:"backfilled"` //Anciens fichiers dont le SHA-256 a été calculé
+	Error      string        `json:"error,omitempty"`
+	Problems   []BlobProblem `json:"problems"`
+}
+
+// Contenu manquant ou corrompu, avec les fichiers concernés
+type BlobProblem struct {
+	Hash           string      `json:"hash,omitempty"` //Vide pour un ancien fichier jamais vérifié
+	Status         string      `json:"status"`         //missing ou corrupt
+	StorageBackend string      `json:"storage_backend"`
+	StorageKey     string      `json:"storage_key"`
+	FileIDs        []uuid.UUID `json:"file_ids"`
+}
+
+// Espace de stockage utilisé et quota applicable
+type StorageUsage struct {
+	UserID         uuid.UUID `json:"user_id"`
+	UsedBytes      int64     `json:"used_bytes"` //Somme des tailles des fichiers et de leurs anciennes versions
+	FileCount      int64     `json:"file_count"`
+	QuotaBytes     *int64    `json:"quota_bytes"` //null : illimité
+	RemainingBytes *int64    `json:"remaining_bytes"`
+	QuotaSource    string    `json:"quota_source"` //user, role, default ou none
+}
+
+type QuotaRequest struct {
+	QuotaBytes *int64 `json:"quota_bytes" binding:"required,min=0"`
+}
+
+// Quotas configurés : valeur par défaut (STORAGE_QUOTA_DEFAULT), par rôle et par utilisateur
+type QuotaList struct {
+	DefaultBytes *int64             `json:"default_quota_bytes"`
+	Roles        []models.RoleQuota `json:"roles"`
+	Users        []models.UserQuota `json:"users"`
+}
+
+// Fichiers à télécharger en une archive ZIP : une liste d'IDs ou tous les fichiers d'un utilisateur
+type ArchiveRequest struct {
+	FileIDs []uuid.UUID `json:"file_ids"`
+	UserID  *uuid.UUID  `json:"user_id"`
+}
+
+// Fichier dans la corbeille : restaurable jusqu'à purge_at
+type TrashedFile struct {
+	models.File
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// Résultat de la réconciliation entre la base et le stockage
+type ReconcileReport struct {
+	ObjectsScanned int             `json:"objects_scanned"`
+	Orphans        []OrphanObject  `json:"orphans"` //Objets stockés qu'aucune ligne ne référence
+	Missing        []MissingObject `json:"missing"` //Lignes dont l'objet n'existe plus
+	Fixed          bool            `json:"fixed"`
+}
+
+type OrphanObject struct {
+	StorageBackend string    `json:"storage_backend"`
+	StorageKey     string    `json:"storage_key"`
+	Size           int64     `json:"size"`
+	ModTime        time.Time `json:"mod_time"`
+}
+
+type MissingObject struct {
+	StorageBackend string    `json:"storage_backend"`
+	StorageKey     string    `json:"storage_key"`
+	FileID         uuid.UUID `json:"file_id"`
+	Version        int       `json:"version"`
+	Current        bool      `json:"current"` //Contenu courant du fichier (sinon ancienne version)
+	Action         string    `json:"action,omitempty"`
+}
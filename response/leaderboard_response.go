@@ -0,0 +1,38 @@
+package response
+
+import "github.com/google/uuid"
+
+// Séries de jours consécutifs avec au moins une tâche complétée, dans le fuseau de l'utilisateur
+type UserStreak struct {
+	UserID           uuid.UUID `json:"user_id"`
+	CurrentStreak    int       `json:"current_streak"` //En cours si le dernier jour est aujourd'hui ou hier
+	LongestStreak    int       `json:"longest_streak"`
+	LastCompletedDay string    `json:"last_completed_day"` //YYYY-MM-DD, vide si aucune tâche complétée
+}
+
+// Ligne du classement : le résumé porte sur la semaine (tâches à traiter et complétées pendant la semaine)
+type LeaderboardEntry struct {
+	Rank int `json:"rank"`
+	UserResume
+	Role          string `json:"role"`
+	Team          string `json:"team"`
+	CurrentStreak int    `json:"current_streak"`
+}
+
+type Leaderboard struct {
+	By        string             `json:"by"`         //completed ou rate
+	WeekStart string             `json:"week_start"` //Lundi de la semaine (YYYY-MM-DD)
+	WeekEnd   string             `json:"week_end"`   //Dimanche de la semaine
+	Timezone  string             `json:"timezone"`
+	Entries   []LeaderboardEntry `json:"entries"`
+}
+
+// Moyennes par utilisateur d'une équipe ou d'un rôle
+type GroupAverage struct {
+	Key               string  `json:"key"` //Nom de l'équipe ou du rôle, vide pour les utilisateurs sans équipe ou rôle
+	Members           int64   `json:"members"`
+	AvgTotalTasks     float64 `json:"avg_total_tasks"`
+	AvgCompletedTasks float64 `json:"avg_completed_tasks"`
+	AvgCompletionRate float64 `json:"avg_completion_rate"`
+	AvgCurrentStreak  float64 `json:"avg_current_streak"`
+}
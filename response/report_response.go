@@ -0,0 +1,9 @@
+package response
+
+import "projet1/models"
+
+// Rapport en arrière-plan ; DownloadURL est renseignée quand il est prêt
+type ReportJob struct {
+	models.Report
+	DownloadURL string `json:"download_url,omitempty"`
+}
@@ -0,0 +1,22 @@
+package response
+
+import (
+	"projet1/models"
+
+	"github.com/google/uuid"
+)
+
+type SearchResult struct {
+	Type    string    `json:"type"` //task, user ou file
+	ID      uuid.UUID `json:"id"`
+	Title   string    `json:"title"`
+	Snippet string    `json:"snippet"` //Extrait en HTML échappé, avec les termes trouvés entre <mark></mark>
+	Rank    float64   `json:"rank"`
+}
+
+// Fichier trouvé par la recherche dans le nom et le texte des documents
+type FileSearchResult struct {
+	models.File
+	Snippet string  `json:"snippet"` //Extrait du texte (ou du nom) en HTML échappé, avec les termes trouvés entre <mark></mark>
+	Rank    float64 `json:"rank"`
+}
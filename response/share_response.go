@@ -0,0 +1,57 @@
+package response
+
+import (
+	"projet1/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShareRequest struct {
+	UserID     uuid.UUID `json:"user_id" binding:"required"`                     //Destinataire du partage
+	Permission string    `json:"permission" binding:"omitempty,oneof=read edit"` //read par défaut
+}
+
+type PublicLinkRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`                                //null : pas d'expiration
+	Password  string     `json:"password" binding:"omitempty,min=4,max=72"` //Vide : lien sans mot de passe
+}
+
+// Partage avec le nom de la ressource partagée
+type SharedResource struct {
+	models.Share
+	ResourceName string `json:"resource_name"` //Nom du fichier ou titre de la tâche
+}
+
+// Lien public vu par son propriétaire
+type PublicLink struct {
+	models.PublicLink
+	URL               string `json:"url"` //Lien à utiliser sans token
+	PasswordProtected bool   `json:"password_protected"`
+	ResourceName      string `json:"resource_name"`
+}
+
+// Ce que l'utilisateur connecté a partagé
+type SharedByMe struct {
+	Shares      []SharedResource `json:"shares"`
+	PublicLinks []PublicLink     `json:"public_links"`
+}
+
+// Ressource consultée avec un lien public
+type PublicResource struct {
+	ResourceType string       `json:"resource_type"`
+	File         *PublicFile  `json:"file,omitempty"`
+	Task         *models.Task `json:"task,omitempty"`
+	DownloadURL  string       `json:"download_url,omitempty"` //Contenu du fichier, avec le même mot de passe
+	ExpiresAt    *time.Time   `json:"expires_at"`
+}
+
+// Informations d'un fichier visibles sans compte (sans les détails de stockage)
+type PublicFile struct {
+	ID        uuid.UUID `json:"id"`
+	FileName  string    `json:"file_name"`
+	MimeType  string    `json:"mime_type"`
+	Size      int64     `json:"file_size"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,12 +1,46 @@
-package response
-
-import "projet1/models"
-
-type CompletionRate struct {
-	Rate string `json:"completion_rate"`
-}
-
-type TaskResult struct {
-	Tasks []models.Task
-	Err   error
-}
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CompletionRate struct {
+	Rate string `json:"completion_rate"`
+}
+
+// Requête d'opération groupée sur les tâches
+type BulkTaskRequest struct {
+	Action    string          `json:"action" binding:"required,oneof=complete reopen delete retag reassign move"`
+	IDs       []uuid.UUID     `json:"ids"`
+	Filter    *BulkTaskFilter `json:"filter"`
+	Tags      []string        `json:"tags"`       //Pour l'action retag
+	UserID    *uuid.UUID      `json:"user_id"`    //Pour l'action reassign
+	ProjectID *uuid.UUID      `json:"project_id"` //Pour l'action move (null pour retirer du projet)
+	DryRun    bool            `json:"dry_run"`
+}
+
+// Sélection des tâches par filtre (alternative à la liste des IDs)
+type BulkTaskFilter struct {
+	UserID        *uuid.UUID `json:"user_id"`
+	ProjectID     *uuid.UUID `json:"project_id"`
+	Completed     *bool      `json:"completed"`
+	Tag           string     `json:"tag"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+}
+
+type BulkItemResult struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"` //updated, unchanged, deleted, not_found, forbidden, failed
+	Error  string    `json:"error,omitempty"`
+}
+
+type BulkTaskResult struct {
+	Action  string           `json:"action"`
+	DryRun  bool             `json:"dry_run"`
+	Matched int              `json:"matched"`
+	Changed int              `json:"changed"`
+	Items   []BulkItemResult `json:"items"`
+}
@@ -1,28 +1,42 @@
-package response
-
-import "github.com/google/uuid"
-
-type UpdateUser struct {
-	Nom    string `json:"nom"`
-	Prenom string `json:"prenom"`
-}
-
-type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
-}
-
-type UserResume struct {
-	ID               uuid.UUID `json:"ID"`
-	Nom              string    `json:"nom"`
-	TotalTasks       int       `json:"total_tasks"`
-	CompletedTasks   int       `json:"compelted_tasks"`
-	CompletedPercent float64   `json:"completed_percent"`
-}
-
-type UserStat struct {
-	TotalUser      int64   `json:"total_user"`
-	TotalTasks     int64   `json:"total_tasks"`
-	TotalCompleted int64   `json:"total_completed"`
-	Rate           float64 `json:"rate"`
-}
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateUser struct {
+	Nom      string `json:"nom"`
+	Prenom   string `json:"prenom"`
+	Timezone string `json:"timezone"` //Fuseau IANA, ex. Europe/Paris
+	Team     string `json:"team"`
+	//Absent : inchangé ; true : l'utilisateur n'apparaît plus dans les classements
+	LeaderboardOptOut *bool `json:"leaderboard_opt_out"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type UserResume struct {
+	ID               uuid.UUID `json:"ID"`
+	Nom              string    `json:"nom"`
+	TotalTasks       int       `json:"total_tasks"`
+	CompletedTasks   int       `json:"compelted_tasks"`
+	CompletedPercent float64   `json:"completed_percent"`
+	OptOut           bool      `gorm:"column:leaderboard_opt_out" json:"-"` //Utilisateur à anonymiser dans le résumé anonyme
+}
+
+type UserStat struct {
+	TotalUser      int64     `json:"total_user"`
+	TotalTasks     int64     `json:"total_tasks"`
+	TotalCompleted int64     `json:"total_completed"`
+	Rate           float64   `json:"rate"`
+	TotalFiles     int64     `json:"total_files"`
+	StorageUsed    int64     `json:"storage_used_bytes"`   //Somme des tailles des fichiers (quotas)
+	StorageStored  int64     `json:"storage_stored_bytes"` //Octets réellement stockés après déduplication
+	ComputedAt     time.Time `json:"computed_at"`          //Date du calcul (plus ancienne si la valeur vient du cache)
+	Cached         bool      `json:"cached"`
+}
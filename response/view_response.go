@@ -0,0 +1,21 @@
+package response
+
+import (
+	"projet1/models"
+
+	"github.com/google/uuid"
+)
+
+type SavedViewRequest struct {
+	Name       string      `json:"name" binding:"required"`
+	Filter     string      `json:"filter"`   //ex. completed:eq:false,due_date:lt:@now
+	Sort       string      `json:"sort"`     //ex. -created_at
+	GroupBy    string      `json:"group_by"` //completed, user_id ou project_id
+	SharedWith []uuid.UUID `json:"shared_with"`
+}
+
+type TaskGroup struct {
+	Key   any           `json:"key"`
+	Count int           `json:"count"`
+	Tasks []models.Task `json:"tasks"`
+}
@@ -1,60 +1,162 @@
-package routes
-
-import (
-	"net/http"
-	"projet1/handlers"
-	"projet1/middleware"
-
-	"github.com/gin-gonic/gin"
-)
-
-func SetupRouter(r *gin.Engine) {
-
-	//Route publique
-	r.POST("/login", handlers.LoginHandler)
-
-	//Routes protégées par middleware
-	protected := r.Group("/api")
-	protected.Use(middleware.AuthMiddleware()) //Syntaxe de l'appel du middleware
-	{
-		users := protected.Group("/users")
-		{
-			users.POST("/", handlers.CreateUser)
-			users.GET("/", handlers.GetUsers)
-			users.GET("/:id", handlers.GetUser)
-			users.PUT("/:id", handlers.UpdateUser)
-			users.DELETE("/:id", handlers.DeleteUser)
-
-			//Routes Fonctionnalités
-			users.GET("/paginated_users", handlers.GetPaginatedUser)
-			users.PATCH("/:id", handlers.UpdateUserPartial)
-			users.GET("/user_by_email", handlers.FindUserByEmail)
-			users.POST("/upload_file/:user_id", handlers.UploadFile) //Route pour importer un fichier
-			users.StaticFS("/files", http.Dir("./upload"))
-			users.GET("/get_file/:file_id", handlers.ServeFile) //Route pour récuperer un fichier de la base
-			users.GET("/user_files/:user_id", handlers.GetUserFiles)
-			users.GET("/paginated_files", handlers.PaginatedFiles)
-			users.GET("/activity_overview_anonyme", handlers.GetAllUsersActivity_anonyme)
-			users.GET("/activity_overview", handlers.GetAllUsersActivity)
-			users.GET("/user_overview", handlers.GetUsersActivity)
-			users.GET("/global_stat", handlers.GlobalStats)
-			users.GET("/global_stat_channel", handlers.GlobalStats_channel)
-
-		}
-
-		tasks := protected.Group("/tasks")
-		{
-			tasks.POST("/", handlers.CreateTask)
-			tasks.GET("/", handlers.GetTasks)
-			tasks.GET("/:id", handlers.GetTask)
-			tasks.PUT("/:id", handlers.UpdateTask)
-			tasks.DELETE("/:id", handlers.DeleteTask)
-
-			//Fonctionnalitées
-			tasks.GET("/paginated", handlers.GetPaginatedTasks)
-			tasks.GET("/filtrer", handlers.FiltrerTask)
-			tasks.GET("/rate/:user_id", handlers.CompletionRate)
-			tasks.GET("/filtre_date", handlers.GetTasksByDate)
-		}
-	}
-}
+package routes
+
+import (
+	"projet1/handlers"
+	"projet1/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine) {
+
+	//Route publique
+	r.POST("/login", handlers.LoginHandler)
+	r.GET("/download/:link_id", handlers.DownloadFile) //Lien signé, sans token
+	r.HEAD("/download/:link_id", handlers.DownloadFile)
+	r.GET("/public/:token", handlers.GetPublicResource) //Lien public, mot de passe éventuel dans X-Link-Password
+	r.GET("/public/:token/download", handlers.DownloadPublicFile)
+	r.HEAD("/public/:token/download", handlers.DownloadPublicFile)
+	r.OPTIONS("/api/uploads/", handlers.TusOptions) //Découverte tus, sans token
+	r.OPTIONS("/api/uploads/:id", handlers.TusOptions)
+
+	//Routes protégées par middleware
+	protected := r.Group("/api")
+	protected.Use(middleware.AuthMiddleware()) //Syntaxe de l'appel du middleware
+	{
+		users := protected.Group("/users")
+		{
+			users.POST("/", handlers.CreateUser)
+			users.GET("/", handlers.GetUsers)
+			users.GET("/:id", handlers.GetUser)
+			users.PUT("/:id", handlers.UpdateUser)
+			users.DELETE("/:id", handlers.DeleteUser)
+
+			//Routes Fonctionnalités
+			users.GET("/paginated_users", handlers.GetPaginatedUser)
+			users.PATCH("/:id", handlers.UpdateUserPartial)
+			users.GET("/user_by_email", handlers.FindUserByEmail)
+			users.POST("/upload_file/:user_id", handlers.UploadFile) //Route pour importer un fichier
+			users.GET("/get_file/:file_id", handlers.ServeFile)      //Route pour récuperer un fichier de la base
+			users.HEAD("/get_file/:file_id", handlers.ServeFile)     //Taille et validateurs avant un téléchargement partiel
+			users.POST("/file_link/:file_id", handlers.CreateDownloadLink)
+			users.DELETE("/file_link/:link_id", handlers.RevokeDownloadLink)
+			users.GET("/user_files/:user_id", handlers.GetUserFiles)
+			users.GET("/paginated_files", handlers.PaginatedFiles)
+			users.GET("/activity_overview_anonyme", handlers.GetAllUsersActivity_anonyme)
+			users.GET("/activity_overview", handlers.GetAllUsersActivity)
+			users.GET("/user_overview", handlers.GetUsersActivity)
+			users.GET("/global_stat", handlers.GlobalStats)
+			users.GET("/global_stat_channel", handlers.GlobalStats_channel)
+
+		}
+
+		tasks := protected.Group("/tasks")
+		{
+			tasks.POST("/", handlers.CreateTask)
+			tasks.GET("/", handlers.GetTasks)
+			tasks.GET("/:id", handlers.GetTask)
+			tasks.PUT("/:id", handlers.UpdateTask)
+			tasks.DELETE("/:id", handlers.DeleteTask)
+
+			//Fonctionnalitées
+			tasks.GET("/paginated", handlers.GetPaginatedTasks)
+			tasks.GET("/filtrer", handlers.FiltrerTask)
+			tasks.GET("/rate/:user_id", handlers.CompletionRate)
+			tasks.GET("/filtre_date", handlers.GetTasksByDate)
+			tasks.POST("/bulk", handlers.BulkTasks)
+
+			//Partage
+			tasks.POST("/:id/shares", handlers.ShareTask)
+			tasks.GET("/:id/shares", handlers.GetTaskShares)
+			tasks.DELETE("/:id/shares/:user_id", handlers.UnshareTask)
+			tasks.POST("/:id/public_links", handlers.CreateTaskPublicLink)
+		}
+
+		protected.GET("/search", handlers.Search)
+
+		analytics := protected.Group("/analytics")
+		{
+			analytics.GET("/tasks", handlers.GetTaskAnalytics)
+			analytics.GET("/streaks", handlers.GetUserStreak)
+			analytics.GET("/leaderboard", handlers.GetLeaderboard)
+			analytics.GET("/averages", handlers.GetGroupAverages)
+		}
+
+		reports := protected.Group("/reports")
+		{
+			reports.GET("/jobs", handlers.GetReportJobs)
+			reports.GET("/jobs/:id", handlers.GetReportJob)
+			reports.GET("/jobs/:id/download", handlers.DownloadReport)
+			reports.GET("/:kind", handlers.GetReport)
+		}
+		protected.GET("/me/storage", handlers.GetMyStorage)
+
+		views := protected.Group("/views")
+		{
+			views.POST("/", handlers.CreateView)
+			views.GET("/", handlers.GetViews)
+			views.GET("/:id", handlers.GetView)
+			views.PUT("/:id", handlers.UpdateView)
+			views.DELETE("/:id", handlers.DeleteView)
+			views.GET("/:id/tasks", handlers.GetViewTasks)
+		}
+
+		//Uploads en plusieurs morceaux (protocole tus)
+		tus := protected.Group("/uploads")
+		tus.Use(middleware.TusMiddleware())
+		{
+			tus.POST("/", handlers.CreateUpload)
+			tus.HEAD("/:id", handlers.HeadUpload)
+			tus.PATCH("/:id", handlers.PatchUpload)
+			tus.DELETE("/:id", handlers.DeleteUpload)
+		}
+
+		files := protected.Group("/files")
+		{
+			files.GET("/search", handlers.SearchFiles)
+			files.POST("/archive", handlers.DownloadArchive)
+			files.GET("/trash", handlers.GetTrash)
+			files.DELETE("/trash/:file_id", handlers.PurgeFile)
+			files.DELETE("/:file_id", handlers.DeleteFile)
+			files.POST("/:file_id/restore", handlers.RestoreFile)
+			files.POST("/:file_id/versions", handlers.UploadFileVersion)
+			files.GET("/:file_id/versions", handlers.GetFileVersions)
+			files.GET("/:file_id/versions/:version", handlers.DownloadFileVersion)
+			files.HEAD("/:file_id/versions/:version", handlers.DownloadFileVersion)
+			files.POST("/:file_id/versions/:version/promote", handlers.PromoteFileVersion)
+			files.GET("/:file_id/preview", handlers.GetFilePreview)
+			files.POST("/:file_id/shares", handlers.ShareFile)
+			files.GET("/:file_id/shares", handlers.GetFileShares)
+			files.DELETE("/:file_id/shares/:user_id", handlers.UnshareFile)
+			files.POST("/:file_id/public_links", handlers.CreateFilePublicLink)
+		}
+
+		shares := protected.Group("/shares")
+		{
+			shares.GET("/", handlers.GetMyShares)
+			shares.GET("/received", handlers.GetSharedWithMe)
+		}
+		protected.DELETE("/public_links/:link_id", handlers.RevokePublicLink)
+
+		//Administration (les handlers vérifient le rôle admin)
+		admin := protected.Group("/admin")
+		{
+			admin.POST("/blobs/verify", handlers.VerifyBlobs)
+			admin.GET("/blobs/verify", handlers.GetBlobReport)
+			admin.GET("/quarantine", handlers.GetQuarantinedFiles)
+			admin.POST("/quarantine/:file_id/rescan", handlers.RescanFile)
+			admin.GET("/quotas", handlers.GetQuotas)
+			admin.GET("/quotas/users/:user_id", handlers.GetUserStorage)
+			admin.PUT("/quotas/users/:user_id", handlers.SetUserQuota)
+			admin.DELETE("/quotas/users/:user_id", handlers.DeleteUserQuota)
+			admin.PUT("/quotas/roles/:role", handlers.SetRoleQuota)
+			admin.DELETE("/quotas/roles/:role", handlers.DeleteRoleQuota)
+		}
+
+		projects := protected.Group("/projects")
+		{
+			projects.POST("/", handlers.CreateProject)
+			projects.GET("/", handlers.GetProjects)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Taille des morceaux envoyés à clamd (StreamMaxLength limite le total côté démon)
+const clamdChunkSize = 64 << 10
+
+// Clamd analyse les fichiers avec un démon ClamAV (commande INSTREAM)
+type Clamd struct {
+	network string //tcp ou unix
+	address string
+	timeout time.Duration
+}
+
+// NewClamd accepte tcp://hôte:port, unix:///chemin/clamd.sock ou directement hôte:port
+func NewClamd(address string, timeout time.Duration) (*Clamd, error) {
+	if !strings.Contains(address, "://") {
+		return &Clamd{network: "tcp", address: address, timeout: timeout}, nil
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("scan: CLAMD_ADDRESS %q invalide: %w", address, err)
+	}
+	switch u.Scheme {
+	case "tcp":
+		return &Clamd{network: "tcp", address: u.Host, timeout: timeout}, nil
+	case "unix":
+		return &Clamd{network: "unix", address: u.Path, timeout: timeout}, nil
+	}
+	return nil, fmt.Errorf("scan: CLAMD_ADDRESS %q: schéma non supporté", address)
+}
+
+func (cl *Clamd) Name() string { return "clamd" }
+
+// Scan envoie le contenu par morceaux (longueur sur 4 octets big-endian puis données,
+// un morceau vide termine le flux) et lit la réponse : "stream: OK" ou "stream: <menace> FOUND"
+func (cl *Clamd) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, cl.network, cl.address)
+	if err != nil {
+		return Result{}, fmt.Errorf("clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(cl.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, fmt.Errorf("clamd: %w", err)
+	}
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, werr := conn.Write(buf[:4+n]); werr != nil {
+				//clamd coupe la connexion quand StreamMaxLength est dépassé : sa réponse explique pourquoi
+				return replyOr(conn, werr)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return Result{}, err
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return replyOr(conn, err)
+	}
+
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return Result{}, err
+	}
+	return parseClamdReply(reply)
+}
+
+// Réponse de clamd après une écriture refusée, sinon l'erreur d'écriture
+func replyOr(conn net.Conn, writeErr error) (Result, error) {
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return Result{}, fmt.Errorf("clamd: %w", writeErr)
+	}
+	return parseClamdReply(reply)
+}
+
+// Lit la réponse terminée par NUL ; une connexion fermée sans réponse est une erreur
+func readClamdReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && (err != io.EOF || reply == "") {
+		return "", fmt.Errorf("clamd: pas de réponse: %w", err)
+	}
+	reply = strings.TrimRight(reply, "\x00\n")
+	if reply == "" {
+		return "", errors.New("clamd: réponse vide")
+	}
+	return reply, nil
+}
+
+func parseClamdReply(reply string) (Result, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	case strings.HasSuffix(reply, " ERROR"):
+		return Result{}, errors.New("clamd: " + strings.TrimSuffix(reply, " ERROR"))
+	}
+	return Result{}, fmt.Errorf("clamd: réponse inattendue %q", reply)
+}
@@ -0,0 +1,147 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd répond à une commande INSTREAM : reply est envoyé (suivi de NUL) une fois le flux
+// terminé, ou dès que plus de maxStream octets sont reçus comme le fait clamd avec StreamMaxLength.
+// noReply ferme la connexion sans répondre. Renvoie l'adresse et les octets reçus par connexion
+func fakeClamd(t *testing.T, reply string, maxStream int, noReply bool) (string, <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		command := make([]byte, len("zINSTREAM\x00"))
+		if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+			t.Errorf("commande reçue %q", command)
+			return
+		}
+		var stream bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&stream, conn, int64(size)); err != nil {
+				return
+			}
+			if maxStream > 0 && stream.Len() > maxStream {
+				break
+			}
+		}
+		received <- stream.Bytes()
+		if noReply {
+			return
+		}
+		conn.Write([]byte(reply + "\x00"))
+		//Comme clamd, la connexion est fermée après la réponse ; le reste du flux est ignoré
+		io.Copy(io.Discard, conn)
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestClamdScan(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		maxStream int
+		noReply   bool
+		size      int
+		want      Result
+		wantErr   string
+	}{
+		{name: "sain", reply: "stream: OK", size: 3*clamdChunkSize + 17, want: Result{}},
+		{name: "fichier vide", reply: "stream: OK", size: 0, want: Result{}},
+		{name: "menace", reply: "stream: Eicar-Test-Signature FOUND", size: 68,
+			want: Result{Infected: true, Signature: "Eicar-Test-Signature"}},
+		{name: "taille maximale", reply: "INSTREAM size limit exceeded. ERROR", maxStream: clamdChunkSize,
+			size: 4 * clamdChunkSize, wantErr: "size limit exceeded"},
+		{name: "erreur", reply: "stream: Can't allocate memory ERROR", size: 10, wantErr: "Can't allocate memory"},
+		{name: "réponse inattendue", reply: "PONG", size: 10, wantErr: "réponse inattendue"},
+		{name: "pas de réponse", noReply: true, size: 10, wantErr: "pas de réponse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, received := fakeClamd(t, tt.reply, tt.maxStream, tt.noReply)
+			cl, err := NewClamd("tcp://"+addr, 5*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := bytes.Repeat([]byte("x"), tt.size)
+
+			got, err := cl.Scan(context.Background(), bytes.NewReader(content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, attendu une erreur contenant %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Scan = %+v, attendu %+v", got, tt.want)
+			}
+			if stream := <-received; !bytes.Equal(stream, content) {
+				t.Errorf("clamd a reçu %d octets, attendu %d", len(stream), len(content))
+			}
+		})
+	}
+}
+
+func TestClamdScanUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	cl, _ := NewClamd(addr, time.Second)
+	if _, err := cl.Scan(context.Background(), strings.NewReader("x")); err == nil {
+		t.Error("Scan sans démon : erreur attendue")
+	}
+}
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		reply   string
+		want    Result
+		wantErr bool
+	}{
+		{"stream: OK", Result{}, false},
+		{"OK", Result{}, false},
+		{"stream: Win.Test.EICAR_HDB-1 FOUND", Result{Infected: true, Signature: "Win.Test.EICAR_HDB-1"}, false},
+		{"INSTREAM size limit exceeded. ERROR", Result{}, true},
+		{"stream: OKAY", Result{}, true},
+		{"", Result{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseClamdReply(tt.reply)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseClamdReply(%q) = %+v, %v", tt.reply, got, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Analyse antivirus des fichiers uploadés
+package scan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Result est le verdict d'une analyse
+type Result struct {
+	Infected  bool
+	Signature string //Nom de la menace détectée
+}
+
+// Scanner est implémenté par chaque moteur d'analyse
+type Scanner interface {
+	Name() string
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
+
+// Current analyse les nouveaux fichiers (SCANNER)
+var Current Scanner = Noop{}
+
+// Load choisit le scanner à partir de SCANNER : noop (par défaut), eicar ou clamd.
+// clamd utilise CLAMD_ADDRESS (tcp://hôte:port ou unix:///chemin) et CLAMD_TIMEOUT
+func Load() error {
+	switch name := strings.TrimSpace(os.Getenv("SCANNER")); name {
+	case "", "noop":
+		Current = Noop{}
+	case "eicar":
+		Current = EICAR{}
+	case "clamd":
+		address := os.Getenv("CLAMD_ADDRESS")
+		if address == "" {
+			address = "tcp://127.0.0.1:3310"
+		}
+		timeout := 2 * time.Minute
+		if value := os.Getenv("CLAMD_TIMEOUT"); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("scan: CLAMD_TIMEOUT %q invalide", value)
+			}
+			timeout = d
+		}
+		clamd, err := NewClamd(address, timeout)
+		if err != nil {
+			return err
+		}
+		Current = clamd
+	default:
+		return fmt.Errorf("scan: SCANNER %q inconnu", name)
+	}
+	return nil
+}
+
+// Noop considère tous les fichiers comme sains (développement, pas d'antivirus disponible)
+type Noop struct{}
+
+func (Noop) Name() string { return "noop" }
+
+func (Noop) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	return Result{}, nil
+}
+
+// Fichier de test antivirus standard (https://www.eicar.org)
+var eicarSignature = []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`)
+
+// EICAR ne détecte que le fichier de test EICAR : permet de tester la quarantaine sans antivirus
+type EICAR struct{}
+
+func (EICAR) Name() string { return "eicar" }
+
+func (EICAR) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Result{}, err
+	}
+	if bytes.Contains(data, eicarSignature) {
+		return Result{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	}
+	return Result{}, nil
+}
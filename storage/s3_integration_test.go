@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Test d'intégration contre un vrai bucket, MinIO en local par exemple :
+//
+//	S3_TEST_ENDPOINT=http://localhost:9000 S3_TEST_BUCKET=test S3_TEST_ACCESS_KEY=minio S3_TEST_SECRET_KEY=minio123 go test ./storage -run Integration
+//
+// Ignoré sans S3_TEST_ENDPOINT. Les objets sont créés sous un préfixe unique et supprimés à la fin
+func TestS3Integration(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT non défini : pas de bucket de test")
+	}
+	region := os.Getenv("S3_TEST_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	s, err := NewS3(S3Config{
+		Endpoint:  endpoint,
+		Region:    region,
+		Bucket:    os.Getenv("S3_TEST_BUCKET"),
+		AccessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	prefix := "integration-" + uuid.NewString() + "/"
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	keys := []string{prefix + "a.txt", prefix + "dossier/b c+é.txt"}
+	t.Cleanup(func() {
+		for _, key := range keys {
+			s.Delete(context.Background(), key)
+		}
+	})
+
+	for _, key := range keys {
+		if err := s.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+
+	t.Run("Stat", func(t *testing.T) {
+		info, err := s.Stat(ctx, keys[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size != int64(len(content)) || !strings.HasPrefix(info.ContentType, "text/plain") {
+			t.Errorf("Stat = %+v", info)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		obj, err := s.Get(ctx, keys[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer obj.Close()
+		got, err := io.ReadAll(obj)
+		if err != nil || !bytes.Equal(got, content) {
+			t.Errorf("Get = %q, %v", got, err)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		tests := []struct {
+			offset int64
+			whence int
+			n      int
+			want   string
+		}{
+			{10, io.SeekStart, 5, "abcde"},
+			{-6, io.SeekEnd, 6, "uvwxyz"},
+			{0, io.SeekStart, 3, "012"},
+		}
+		obj, err := s.Get(ctx, keys[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer obj.Close()
+		for _, tt := range tests {
+			if _, err := obj.Seek(tt.offset, tt.whence); err != nil {
+				t.Fatal(err)
+			}
+			buf := make([]byte, tt.n)
+			if _, err := io.ReadFull(obj, buf); err != nil || string(buf) != tt.want {
+				t.Errorf("Seek(%d, %d) puis lecture = %q, %v ; attendu %q", tt.offset, tt.whence, buf, err, tt.want)
+			}
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var listed []string
+		err := s.List(ctx, prefix, func(info ObjectInfo) error {
+			listed = append(listed, info.Key)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(listed, ",") != strings.Join(keys, ",") {
+			t.Errorf("List = %v, attendu %v", listed, keys)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := s.Delete(ctx, keys[0]); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Get(ctx, keys[0]); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get après Delete : %v, attendu ErrNotFound", err)
+		}
+	})
+}
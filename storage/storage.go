@@ -0,0 +1,97 @@
+// Stockage des fichiers uploadés (disque local ou S3 compatible)
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("storage: objet introuvable")
+	ErrNotSupported = errors.New("storage: opération non supportée par ce backend")
+)
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage est implémenté par chaque backend. Les clés sont des chemins relatifs
+// séparés par "/" (ex. "3f2c...e1.pdf")
+type Storage interface {
+	Name() string
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	Presign(ctx context.Context, key string, expires time.Duration) (string, error)
+	// List appelle fn pour chaque objet dont la clé commence par prefix ("" : tous)
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}
+
+var (
+	// Default reçoit les nouveaux fichiers
+	Default Storage
+
+	// Backends permet de relire un fichier avec le backend enregistré dans models.File
+	Backends = map[string]Storage{}
+)
+
+// Connect configure les backends à partir des variables d'environnement.
+// Le disque local est toujours disponible pour les fichiers existants
+func Connect() {
+	local, err := NewLocal(getenv("STORAGE_LOCAL_DIR", "upload"))
+	if err != nil {
+		log.Fatal("Erreur d'initialisation du stockage local:", err)
+	}
+	Backends[local.Name()] = local
+	Default = local
+
+	if os.Getenv("S3_BUCKET") != "" {
+		pathStyle, _ := strconv.ParseBool(getenv("S3_PATH_STYLE", "true"))
+		s3, err := NewS3(S3Config{
+			Endpoint:  getenv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:    getenv("S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: pathStyle,
+		})
+		if err != nil {
+			log.Fatal("Erreur d'initialisation du stockage S3:", err)
+		}
+		Backends[s3.Name()] = s3
+	}
+
+	backend := getenv("STORAGE_BACKEND", "local")
+	if Default = Backends[backend]; Default == nil {
+		log.Fatal("Backend de stockage inconnu ou non configuré: ", backend)
+	}
+	log.Printf("Stockage des fichiers : %s", backend)
+}
+
+// Get renvoie le backend par son nom ; un nom vide désigne le disque local (anciens fichiers)
+func Get(name string) (Storage, error) {
+	if name == "" {
+		name = "local"
+	}
+	if s, ok := Backends[name]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("storage: backend %q non configuré", name)
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,323 @@
+// Règles d'acceptation des fichiers uploadés : type détecté à partir du contenu,
+// liste blanche configurable, taille maximale par type et nom de fichier assaini
+package uploads
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+var (
+	ErrEmptyFile       = errors.New("uploads: fichier vide")
+	ErrTypeNotAllowed  = errors.New("uploads: type de fichier non autorisé")
+	ErrTypeMismatch    = errors.New("uploads: l'extension ne correspond pas au contenu")
+	ErrTooLarge        = errors.New("uploads: fichier trop volumineux")
+	ErrInvalidFileName = errors.New("uploads: nom de fichier invalide")
+)
+
+// Nombre d'octets lus pour la détection du type
+const detectionLimit = 64 << 10
+
+// Longueur maximale d'un nom de fichier (colonne file_name)
+const maxFileNameLength = 100
+
+// Rule autorise un type MIME, avec les extensions acceptées et une taille maximale
+type Rule struct {
+	MimeType   string
+	Extensions []string
+	MaxSize    int64
+}
+
+type Policy struct {
+	Rules []Rule
+}
+
+const mb = 1 << 20
+
+// Types acceptés par défaut (les mêmes que les anciennes extensions autorisées)
+var DefaultRules = []Rule{
+	{MimeType: "application/pdf", Extensions: []string{".pdf"}, MaxSize: 20 * mb},
+	{MimeType: "application/msword", Extensions: []string{".doc"}, MaxSize: 10 * mb},
+	{MimeType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", Extensions: []string{".docx"}, MaxSize: 10 * mb},
+}
+
+// Extensions usuelles des types qu'on peut ajouter par configuration
+var knownExtensions = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/png":       {".png"},
+	"image/gif":       {".gif"},
+	"image/webp":      {".webp"},
+	"image/tiff":      {".tif", ".tiff"},
+	"text/plain":      {".txt"},
+	"text/csv":        {".csv"},
+	"application/zip": {".zip"},
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {".xlsx"},
+	"application/vnd.ms-excel":                {".xls"},
+	"application/vnd.oasis.opendocument.text": {".odt"},
+}
+
+// Current est la politique appliquée par les handlers d'upload
+var Current = Policy{Rules: DefaultRules}
+
+// Load lit UPLOAD_ALLOWED_TYPES, liste de "type/mime=taille" séparés par des virgules,
+// ex. "application/pdf=20MB,image/png=5MB". Sans la variable, les règles par défaut s'appliquent.
+// La configuration des uploads en plusieurs morceaux (tus) est lue en même temps
+func Load() error {
+	//Les formats Office (docx, xlsx...) sont des zip : leurs signatures peuvent être loin du début
+	mimetype.SetLimit(detectionLimit)
+
+	Current = Policy{Rules: DefaultRules}
+	if value := strings.TrimSpace(os.Getenv("UPLOAD_ALLOWED_TYPES")); value != "" {
+		policy, err := ParsePolicy(value)
+		if err != nil {
+			return err
+		}
+		Current = policy
+	}
+
+	return loadTus()
+}
+
+func ParsePolicy(value string) (Policy, error) {
+	var p Policy
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		mt, sizeStr, ok := strings.Cut(item, "=")
+		if !ok {
+			return Policy{}, fmt.Errorf("uploads: règle %q invalide, format attendu type/mime=taille", item)
+		}
+		mt = strings.ToLower(strings.TrimSpace(mt))
+		size, err := ParseSize(sizeStr)
+		if err != nil {
+			return Policy{}, fmt.Errorf("uploads: règle %q: %w", item, err)
+		}
+		exts := extensionsFor(mt)
+		if len(exts) == 0 {
+			return Policy{}, fmt.Errorf("uploads: type %q inconnu", mt)
+		}
+		p.Rules = append(p.Rules, Rule{MimeType: mt, Extensions: exts, MaxSize: size})
+	}
+	if len(p.Rules) == 0 {
+		return Policy{}, errors.New("uploads: aucun type autorisé")
+	}
+	return p, nil
+}
+
+// ParseSize accepte un nombre d'octets suivi éventuellement de KB, MB ou GB
+func ParseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	unit := int64(1)
+	for suffix, factor := range map[string]int64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30} {
+		if strings.HasSuffix(value, suffix) {
+			value, unit = strings.TrimSpace(strings.TrimSuffix(value, suffix)), factor
+			break
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("taille %q invalide", value)
+	}
+	return n * unit, nil
+}
+
+func extensionsFor(mt string) []string {
+	for _, r := range DefaultRules {
+		if r.MimeType == mt {
+			return r.Extensions
+		}
+	}
+	if exts, ok := knownExtensions[mt]; ok {
+		return exts
+	}
+	if m := mimetype.Lookup(mt); m != nil && m.Extension() != "" {
+		return []string{m.Extension()}
+	}
+	return nil
+}
+
+// Result décrit un fichier accepté
+type Result struct {
+	FileName  string //Nom assaini, à afficher et à enregistrer
+	Extension string //Extension en minuscules, cohérente avec le contenu
+	MimeType  string //Type détecté à partir du contenu
+}
+
+// RejectError précise la raison du refus ; errors.Is fonctionne avec les erreurs Err*
+type RejectError struct {
+	Reason   error
+	Detected string
+	Limit    int64
+}
+
+func (e *RejectError) Error() string {
+	switch {
+	case e.Reason == ErrTooLarge:
+		return fmt.Sprintf("%s (maximum %d octets pour %s)", e.Reason, e.Limit, e.Detected)
+	case e.Detected != "":
+		return fmt.Sprintf("%s (type détecté : %s)", e.Reason, e.Detected)
+	}
+	return e.Reason.Error()
+}
+
+func (e *RejectError) Unwrap() error {
+	return e.Reason
+}
+
+// Check détecte le type réel du contenu (signatures binaires) et vérifie qu'il est
+// autorisé, que l'extension du nom lui correspond et que la taille respecte la limite.
+// Le lecteur est consommé : il faut revenir au début avant de stocker le fichier
+func (p Policy) Check(filename string, size int64, content io.Reader) (Result, error) {
+	if size <= 0 {
+		return Result{}, &RejectError{Reason: ErrEmptyFile}
+	}
+
+	name := SanitizeFileName(filename)
+	if name == "" {
+		return Result{}, &RejectError{Reason: ErrInvalidFileName}
+	}
+
+	detected, err := mimetype.DetectReader(content)
+	if err != nil {
+		return Result{}, err
+	}
+
+	rule, ok := p.match(detected)
+	if !ok {
+		return Result{}, &RejectError{Reason: ErrTypeNotAllowed, Detected: detected.String()}
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if !contains(rule.Extensions, ext) {
+		return Result{}, &RejectError{Reason: ErrTypeMismatch, Detected: rule.MimeType}
+	}
+
+	if size > rule.MaxSize {
+		return Result{}, &RejectError{Reason: ErrTooLarge, Detected: rule.MimeType, Limit: rule.MaxSize}
+	}
+
+	return Result{FileName: name, Extension: ext, MimeType: rule.MimeType}, nil
+}
+
+// CheckDeclared vérifie le nom et la taille annoncés avant de recevoir le contenu
+// (uploads en plusieurs morceaux) ; le contenu est vérifié avec Check une fois complet
+func (p Policy) CheckDeclared(filename string, size int64) error {
+	if size <= 0 {
+		return &RejectError{Reason: ErrEmptyFile}
+	}
+	name := SanitizeFileName(filename)
+	if name == "" {
+		return &RejectError{Reason: ErrInvalidFileName}
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, r := range p.Rules {
+		if !contains(r.Extensions, ext) {
+			continue
+		}
+		if size > r.MaxSize {
+			return &RejectError{Reason: ErrTooLarge, Detected: r.MimeType, Limit: r.MaxSize}
+		}
+		return nil
+	}
+	return &RejectError{Reason: ErrTypeNotAllowed}
+}
+
+// Règle du type détecté ; les parents sont ignorés (un .docx est un zip mais n'autorise pas les zip)
+func (p Policy) match(detected *mimetype.MIME) (Rule, bool) {
+	for _, r := range p.Rules {
+		if detected.Is(r.MimeType) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// MaxSize renvoie la plus grande taille autorisée, tous types confondus
+func (p Policy) MaxSize() int64 {
+	var max int64
+	for _, r := range p.Rules {
+		if r.MaxSize > max {
+			max = r.MaxSize
+		}
+	}
+	return max
+}
+
+// SanitizeFileName garde uniquement le nom (sans chemin), retire les caractères de contrôle
+// et ceux interdits par les systèmes de fichiers courants, et limite la longueur en
+// conservant l'extension. Renvoie "" si rien d'utilisable ne reste
+func SanitizeFileName(filename string) string {
+	//Les navigateurs anciens envoient parfois le chemin complet (C:\Users\...\cv.pdf)
+	if i := strings.LastIndexAny(filename, `/\`); i >= 0 {
+		filename = filename[i+1:]
+	}
+	if !utf8.ValidString(filename) {
+		filename = strings.ToValidUTF8(filename, "_")
+	}
+
+	var b strings.Builder
+	lastUnderscore := false
+	for _, r := range filename {
+		switch {
+		case unicode.IsControl(r) || r == unicode.ReplacementChar:
+			continue
+		case strings.ContainsRune(`<>:"|?*`, r) || unicode.IsSpace(r) && r != ' ':
+			r = '_'
+		}
+		if r == '_' && lastUnderscore {
+			continue
+		}
+		lastUnderscore = r == '_'
+		b.WriteRune(r)
+	}
+	name := strings.Trim(b.String(), " .")
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSpace(strings.TrimSuffix(name, ext))
+	if base == "" {
+		return ""
+	}
+	if len(name) > maxFileNameLength {
+		//Une "extension" démesurée n'en est pas une : elle est tronquée avec le reste du nom
+		if len(ext) > maxFileNameLength/2 {
+			base, ext = name, ""
+		}
+		base = truncateUTF8(base, maxFileNameLength-len(ext))
+		name = strings.TrimSpace(base) + ext
+	}
+	return name
+}
+
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package uploads
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+	}{
+		{"nom simple", "rapport.pdf", "rapport.pdf"},
+		{"chemin Windows", `C:\Users\moi\cv.pdf`, "cv.pdf"},
+		{"chemin Unix", "../../etc/passwd", "passwd"},
+		{"caractères interdits", `a<b>c:d"e|f?g*.txt`, "a_b_c_d_e_f_g_.txt"},
+		{"contrôles retirés", "a\x00b\x1fc.txt", "abc.txt"},
+		{"tabulation et retour à la ligne", "a\tb\nc.txt", "abc.txt"},
+		{"espace insécable", "a\u00a0b.txt", "a_b.txt"},
+		{"soulignés fusionnés", "a<>:b.txt", "a_b.txt"},
+		{"points et espaces en bordure", " . rapport.pdf. ", "rapport.pdf"},
+		{"accents conservés", "été 2024.odt", "été 2024.odt"},
+		{"UTF-8 invalide", "a\xffb.txt", "a_b.txt"},
+		{"point initial retiré", ".pdf", "pdf"},
+		{"rien d'utilisable", " .. ", ""},
+		{"vide", "", ""},
+		{"nom long", strings.Repeat("a", 150) + ".pdf", strings.Repeat("a", 96) + ".pdf"},
+		{"nom long accentué", strings.Repeat("é", 80) + ".pdf", strings.Repeat("é", 48) + ".pdf"},
+		{"extension très longue", "a." + strings.Repeat("x", 200), "a." + strings.Repeat("x", 98)},
+		{"extension plus longue que la limite", "rapport." + strings.Repeat("é", 120), "rapport." + strings.Repeat("é", 46)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeFileName(tt.filename)
+			if got != tt.want {
+				t.Errorf("SanitizeFileName(%q) = %q, attendu %q", tt.filename, got, tt.want)
+			}
+			if len(got) > maxFileNameLength || !utf8.ValidString(got) {
+				t.Errorf("%q : plus de %d octets ou UTF-8 invalide", got, maxFileNameLength)
+			}
+		})
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"abc", 5, "abc"},
+		{"abc", 2, "ab"},
+		{"été", 2, "é"},
+		{"été", 3, "ét"},
+		{"été", 1, ""},
+		{"abc", 0, ""},
+		{"abc", -51, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateUTF8(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, attendu %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"1024", 1024, false},
+		{"5MB", 5 << 20, false},
+		{"2GB", 2 << 30, false},
+		{"10kb", 10 << 10, false},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"-5MB", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.value)
+		if (err != nil) != tt.wantErr || !tt.wantErr && got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v ; attendu %d", tt.value, got, err, tt.want)
+		}
+	}
+}
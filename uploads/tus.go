@@ -0,0 +1,123 @@
+package uploads
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version du protocole tus supportée (https://tus.io/protocols/resumable-upload)
+const (
+	TusVersion    = "1.0.0"
+	TusExtensions = "creation,termination,expiration"
+)
+
+// Renvoyé quand les morceaux d'un upload enregistré sont absents de PartsDir : avec plusieurs
+// instances, UPLOAD_PARTS_DIR doit être un volume partagé (le verrou de l'upload est en base)
+var ErrPartMissing = errors.New("uploads: morceaux introuvables, UPLOAD_PARTS_DIR doit être partagé entre les instances")
+
+var (
+	// Répertoire des morceaux reçus, quel que soit le backend final. Avec plusieurs instances,
+	// ce doit être un volume partagé : un PATCH peut arriver sur n'importe laquelle
+	PartsDir = filepath.Join(os.TempDir(), "projet1-uploads")
+
+	// Durée de vie d'un upload incomplet sans nouvelle activité
+	Expiration = 24 * time.Hour
+)
+
+// loadTus lit UPLOAD_PARTS_DIR et UPLOAD_EXPIRATION (durée Go, ex. "12h")
+func loadTus() error {
+	if dir := os.Getenv("UPLOAD_PARTS_DIR"); dir != "" {
+		PartsDir = dir
+	}
+	if value := os.Getenv("UPLOAD_EXPIRATION"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("uploads: UPLOAD_EXPIRATION %q invalide", value)
+		}
+		Expiration = d
+	}
+	return os.MkdirAll(PartsDir, 0o755)
+}
+
+func PartPath(id uuid.UUID) string {
+	return filepath.Join(PartsDir, id.String()+".part")
+}
+
+// CreatePart crée le fichier vide qui recevra les morceaux
+func CreatePart(id uuid.UUID) error {
+	f, err := os.OpenFile(PartPath(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// AppendPart écrit au plus max octets de r à la position offset et renvoie le nombre
+// d'octets écrits, même en cas d'erreur (connexion coupée) : ils restent acquis.
+// Ce qui dépasse offset (écriture interrompue avant l'enregistrement en base) est écrasé
+func AppendPart(id uuid.UUID, offset int64, r io.Reader, max int64) (int64, error) {
+	f, err := os.OpenFile(PartPath(id), os.O_WRONLY, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, ErrPartMissing
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, max))
+	if syncErr := f.Sync(); err == nil {
+		err = syncErr
+	}
+	return n, err
+}
+
+func OpenPart(id uuid.UUID) (*os.File, error) {
+	f, err := os.Open(PartPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrPartMissing
+	}
+	return f, err
+}
+
+// RemovePart supprime les morceaux reçus ; un fichier déjà absent n'est pas une erreur
+func RemovePart(id uuid.UUID) error {
+	err := os.Remove(PartPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ParseMetadata décode l'en-tête Upload-Metadata : paires "clé valeur_base64" séparées par des virgules
+func ParseMetadata(header string) (map[string]string, error) {
+	meta := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || key == "" {
+			return nil, fmt.Errorf("uploads: Upload-Metadata invalide pour la clé %q", key)
+		}
+		meta[key] = string(value)
+	}
+	return meta, nil
+}
@@ -0,0 +1,118 @@
+package uploads
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Chaque test écrit ses morceaux dans un répertoire temporaire
+func tempPartsDir(t *testing.T) {
+	t.Helper()
+	previous := PartsDir
+	PartsDir = t.TempDir()
+	t.Cleanup(func() { PartsDir = previous })
+}
+
+func TestAppendPartOffsets(t *testing.T) {
+	type patch struct {
+		offset int64
+		data   string
+		max    int64
+		wantN  int64
+	}
+	tests := []struct {
+		name    string
+		patches []patch
+		want    string
+	}{
+		{"morceaux successifs", []patch{
+			{0, "bonjour ", 100, 8},
+			{8, "le monde", 100, 8},
+		}, "bonjour le monde"},
+		{"limite max", []patch{
+			{0, "0123456789", 4, 4},
+			{4, "456789", 100, 6},
+		}, "0123456789"},
+		//Un PATCH interrompu a écrit au-delà de l'offset enregistré : la reprise l'écrase
+		{"reprise après interruption", []patch{
+			{0, "abcdefXXXX", 100, 10},
+			{6, "ghij", 100, 4},
+		}, "abcdefghij"},
+		{"morceau vide", []patch{
+			{0, "abc", 100, 3},
+			{3, "", 100, 0},
+		}, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempPartsDir(t)
+			id := uuid.New()
+			if err := CreatePart(id); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range tt.patches {
+				n, err := AppendPart(id, p.offset, strings.NewReader(p.data), p.max)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if n != p.wantN {
+					t.Errorf("AppendPart(offset %d) = %d octets, attendu %d", p.offset, n, p.wantN)
+				}
+			}
+			got, err := os.ReadFile(PartPath(id))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("contenu = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartMissing(t *testing.T) {
+	tempPartsDir(t)
+	id := uuid.New()
+
+	if _, err := AppendPart(id, 0, strings.NewReader("x"), 1); !errors.Is(err, ErrPartMissing) {
+		t.Errorf("AppendPart sans morceaux : %v, attendu ErrPartMissing", err)
+	}
+	if _, err := OpenPart(id); !errors.Is(err, ErrPartMissing) {
+		t.Errorf("OpenPart sans morceaux : %v, attendu ErrPartMissing", err)
+	}
+	if err := RemovePart(id); err != nil {
+		t.Errorf("RemovePart d'un fichier absent : %v", err)
+	}
+	if err := CreatePart(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreatePart(id); err == nil {
+		t.Error("CreatePart sur un upload existant : erreur attendue")
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"", map[string]string{}, false},
+		{"filename cmFwcG9ydC5wZGY=", map[string]string{"filename": "rapport.pdf"}, false},
+		{"filename w6l0w6kudHh0, filetype dGV4dC9wbGFpbg==", map[string]string{"filename": "été.txt", "filetype": "text/plain"}, false},
+		{"is_confidential", map[string]string{"is_confidential": ""}, false},
+		{" filename cmFwcG9ydC5wZGY= , ", map[string]string{"filename": "rapport.pdf"}, false},
+		{"filename pas-du-base64!", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMetadata(tt.header)
+		if (err != nil) != tt.wantErr || !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMetadata(%q) = %v, %v ; attendu %v", tt.header, got, err, tt.want)
+		}
+	}
+}
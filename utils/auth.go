@@ -1,41 +1,49 @@
-package utils
-
-import (
-	"projet1/models"
-	"time"
-
-	"github.com/golang-jwt/jwt/v4"
-	"github.com/google/uuid"
-)
-
-var secretKey = []byte("projet_user")
-
-func GenerateToken(userID uuid.UUID) (string, error) {
-	claims := models.Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secretKey)
-}
-
-// Fonction pour valider le token
-func ValidateToken(tokenString string) (*models.Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &models.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(*models.Claims)
-	if !ok {
-		return nil, err
-	}
-
-	return claims, nil
-}
+package utils
+
+import (
+	"projet1/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+var secretKey = []byte("projet_user")
+
+func GenerateToken(userID uuid.UUID) (string, error) {
+	claims := models.Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secretKey)
+}
+
+// Fonction pour valider le token
+func ValidateToken(tokenString string) (*models.Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &models.Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return secretKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*models.Claims)
+	if !ok {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// Récupère l'ID de l'utilisateur connecté (ajouté au contexte par AuthMiddleware)
+func CurrentUserID(c *gin.Context) uuid.UUID {
+	userID, _ := c.Get("user_id")
+	id, _ := userID.(uuid.UUID)
+	return id
+}
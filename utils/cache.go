@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// TTLCache garde en mémoire des résultats pendant ttl, avec leur date de calcul.
+// Clear invalide tout : un calcul commencé avant l'invalidation n'est pas enregistré
+//
+//	stats, computedAt, cached, err := cache.GetOrCompute("global", computeStats)
+type TTLCache[V any] struct {
+	ttl        time.Duration
+	maxEntries int
+	mu         sync.Mutex
+	entries    map[string]cacheEntry[V]
+	generation uint64 //Incrémenté par Clear
+}
+
+type cacheEntry[V any] struct {
+	value      V
+	computedAt time.Time
+}
+
+// NewTTLCache crée un cache d'au plus maxEntries entrées ; ttl <= 0 désactive le cache
+func NewTTLCache[V any](ttl time.Duration, maxEntries int) *TTLCache[V] {
+	return &TTLCache[V]{ttl: ttl, maxEntries: maxEntries, entries: map[string]cacheEntry[V]{}}
+}
+
+// GetOrCompute renvoie la valeur en cache si elle a moins de ttl, sinon la calcule avec compute
+// et l'enregistre. cached indique si la valeur vient du cache, computedAt quand elle a été calculée
+func (c *TTLCache[V]) GetOrCompute(key string, compute func() (V, error)) (value V, computedAt time.Time, cached bool, err error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && now.Sub(entry.computedAt) < c.ttl {
+		return entry.value, entry.computedAt, true, nil
+	}
+
+	if value, err = compute(); err != nil {
+		return value, now, false, err
+	}
+	if c.ttl <= 0 {
+		return value, now, false, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return value, now, false, nil
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.evictExpired(now)
+	}
+	if len(c.entries) < c.maxEntries {
+		c.entries[key] = cacheEntry[V]{value: value, computedAt: now}
+	}
+	return value, now, false, nil
+}
+
+// Clear vide le cache
+func (c *TTLCache[V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]cacheEntry[V]{}
+	c.generation++
+}
+
+// TTL renvoie la durée de validité des entrées
+func (c *TTLCache[V]) TTL() time.Duration {
+	return c.ttl
+}
+
+func (c *TTLCache[V]) evictExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if now.Sub(entry.computedAt) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+}
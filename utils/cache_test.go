@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTTLCacheExpiry(t *testing.T) {
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		wait       time.Duration
+		wantCached bool
+	}{
+		{"valeur fraîche", time.Hour, 0, true},
+		{"valeur expirée", 20 * time.Millisecond, 40 * time.Millisecond, false},
+		{"cache désactivé", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewTTLCache[int](tt.ttl, 10)
+			calls := 0
+			compute := func() (int, error) {
+				calls++
+				return calls, nil
+			}
+
+			if _, _, cached, err := cache.GetOrCompute("k", compute); err != nil || cached {
+				t.Fatalf("premier appel : cached=%v err=%v", cached, err)
+			}
+			time.Sleep(tt.wait)
+			value, _, cached, err := cache.GetOrCompute("k", compute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached != tt.wantCached {
+				t.Errorf("cached = %v, attendu %v", cached, tt.wantCached)
+			}
+			wantValue := 2
+			if tt.wantCached {
+				wantValue = 1
+			}
+			if value != wantValue {
+				t.Errorf("valeur = %d, attendu %d", value, wantValue)
+			}
+		})
+	}
+}
+
+func TestTTLCacheErrorNotCached(t *testing.T) {
+	cache := NewTTLCache[int](time.Hour, 10)
+	failure := errors.New("échec")
+	if _, _, _, err := cache.GetOrCompute("k", func() (int, error) { return 0, failure }); !errors.Is(err, failure) {
+		t.Fatalf("err = %v, attendu %v", err, failure)
+	}
+	value, _, cached, err := cache.GetOrCompute("k", func() (int, error) { return 42, nil })
+	if err != nil || cached || value != 42 {
+		t.Errorf("après une erreur : value=%d cached=%v err=%v, attendu un nouveau calcul", value, cached, err)
+	}
+}
+
+func TestTTLCacheMaxEntries(t *testing.T) {
+	cache := NewTTLCache[string](time.Hour, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		cache.GetOrCompute(key, func() (string, error) { return key, nil })
+	}
+	if _, _, cached, _ := cache.GetOrCompute("c", func() (string, error) { return "c", nil }); cached {
+		t.Error("c ne devait pas être gardé : le cache était plein")
+	}
+	if _, _, cached, _ := cache.GetOrCompute("a", func() (string, error) { return "a", nil }); !cached {
+		t.Error("a devait être en cache")
+	}
+}
+
+func TestTTLCacheClear(t *testing.T) {
+	cache := NewTTLCache[int](time.Hour, 10)
+	cache.GetOrCompute("k", func() (int, error) { return 1, nil })
+	cache.Clear()
+	if _, _, cached, _ := cache.GetOrCompute("k", func() (int, error) { return 2, nil }); cached {
+		t.Error("la valeur devait être recalculée après Clear")
+	}
+}
+
+// Un calcul commencé avant Clear ne doit pas remettre en cache l'état d'avant l'invalidation
+func TestTTLCacheClearDuringCompute(t *testing.T) {
+	cache := NewTTLCache[int](time.Hour, 10)
+	value, _, _, err := cache.GetOrCompute("k", func() (int, error) {
+		cache.Clear()
+		return 1, nil
+	})
+	if err != nil || value != 1 {
+		t.Fatalf("value=%d err=%v", value, err)
+	}
+	value, _, cached, _ := cache.GetOrCompute("k", func() (int, error) { return 2, nil })
+	if cached || value != 2 {
+		t.Errorf("value=%d cached=%v : le calcul invalidé a été gardé", value, cached)
+	}
+}
+
+func TestTTLCacheConcurrentGetOrCompute(t *testing.T) {
+	cache := NewTTLCache[int](time.Hour, 100)
+	var calls atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%10 == 0 {
+				cache.Clear()
+			}
+			value, _, _, err := cache.GetOrCompute("k", func() (int, error) {
+				calls.Add(1)
+				return 7, nil
+			})
+			if err != nil || value != 7 {
+				t.Errorf("value=%d err=%v", value, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if calls.Load() == 0 {
+		t.Fatal("aucun calcul")
+	}
+	//Une fois les écritures terminées, la valeur est servie depuis le cache
+	cache.GetOrCompute("k", func() (int, error) { return 7, nil })
+	if _, _, cached, _ := cache.GetOrCompute("k", func() (int, error) { return 7, nil }); !cached {
+		t.Error("la valeur devait être en cache")
+	}
+}
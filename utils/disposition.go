@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContentDisposition construit l'en-tête Content-Disposition (RFC 6266) : filename en ASCII
+// pour les anciens clients et filename* encodé en UTF-8 (RFC 8187) pour les accents
+func ContentDisposition(disposition, filename string) string {
+	var ascii, encoded strings.Builder
+	for _, r := range filename {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			continue
+		case r > 0x7e || r == '"' || r == '\\':
+			ascii.WriteByte('_')
+		default:
+			ascii.WriteRune(r)
+		}
+	}
+	for _, b := range []byte(filename) {
+		if isAttrChar(b) {
+			encoded.WriteByte(b)
+		} else if b >= 0x20 && b != 0x7f {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	if ascii.String() == encoded.String() {
+		return fmt.Sprintf(`%s; filename="%s"`, disposition, ascii.String())
+	}
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, ascii.String(), encoded.String())
+}
+
+// Caractères autorisés sans encodage dans filename* (attr-char de la RFC 8187)
+func isAttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
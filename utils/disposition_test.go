@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		filename    string
+		want        string
+	}{
+		{"ASCII", "attachment", "rapport.pdf", `attachment; filename="rapport.pdf"`},
+		{"inline", "inline", "photo.png", `inline; filename="photo.png"`},
+		{"accents", "attachment", "été.txt", `attachment; filename="_t_.txt"; filename*=UTF-8''%C3%A9t%C3%A9.txt`},
+		{"espace", "attachment", "mon rapport.pdf", `attachment; filename="mon rapport.pdf"; filename*=UTF-8''mon%20rapport.pdf`},
+		{"guillemets et antislash", "attachment", `a"b\c.txt`, `attachment; filename="a_b_c.txt"; filename*=UTF-8''a%22b%5Cc.txt`},
+		{"retour à la ligne retiré", "attachment", "a\r\nb.txt", `attachment; filename="ab.txt"`},
+		{"point-virgule", "attachment", "a;b.txt", `attachment; filename="a;b.txt"; filename*=UTF-8''a%3Bb.txt`},
+		{"caractères attr-char", "attachment", "a!#$&+-.^_`|~.txt", "attachment; filename=\"a!#$&+-.^_`|~.txt\""},
+		{"vide", "attachment", "", `attachment; filename=""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContentDisposition(tt.disposition, tt.filename); got != tt.want {
+				t.Errorf("ContentDisposition(%q, %q) =\n%s\nattendu\n%s", tt.disposition, tt.filename, got, tt.want)
+			}
+		})
+	}
+}
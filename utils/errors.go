@@ -1,65 +1,221 @@
-package utils
-
-import "net/http"
-
-type AppError struct {
-	Code    string
-	Message string
-	Status  int
-}
-
-var (
-	ErrInvalidCrendentials = AppError{
-		Code:    "INVALID_CRENDENTIALS",
-		Message: "Email ou mot de passe incorrect",
-		Status:  http.StatusUnauthorized,
-	}
-
-	ErrUserNotFound = AppError{
-		Code:    "USER_NOT_FOUND",
-		Message: "Utilisatuer introuvable",
-		Status:  http.StatusNotFound,
-	}
-
-	ErrRecordNotFound = AppError{
-		Code:    "RECORD_NOT_FOUND",
-		Message: "Enregistrement introuvable",
-		Status:  http.StatusNotFound,
-	}
-
-	ErrBadRequest = AppError{
-		Code:    "BAD_REQUEST",
-		Message: "Requête invalide",
-		Status:  http.StatusBadRequest,
-	}
-
-	ErrInternal = AppError{
-		Code:    "INTERNAL_ERROR",
-		Message: "Une erreur interne est survenue",
-		Status:  http.StatusInternalServerError,
-	}
-
-	ErrTokenMissing = AppError{
-		Code:    "TOKEN_MISSING",
-		Message: "Token manqant dans l'en-tête Authorization",
-		Status:  http.StatusUnauthorized,
-	}
-
-	ErrInvalidToken = AppError{
-		Code:    "INVALID_TOKEN",
-		Message: "Token invalide ou expiré",
-		Status:  http.StatusUnauthorized,
-	}
-
-	ErrAccessDenied = AppError{
-		Code:    "ACCESS_DENIED",
-		Message: "Accès refusé",
-		Status:  http.StatusForbidden,
-	}
-
-	ErrValidationFailed = AppError{
-		Code:    "VALIDATION_FAILED",
-		Message: "Echec de validation des données envoyées",
-		Status:  http.StatusUnprocessableEntity,
-	}
-)
+package utils
+
+import "net/http"
+
+type AppError struct {
+	Code    string
+	Message string
+	Status  int
+}
+
+var (
+	ErrInvalidCrendentials = AppError{
+		Code:    "INVALID_CRENDENTIALS",
+		Message: "Email ou mot de passe incorrect",
+		Status:  http.StatusUnauthorized,
+	}
+
+	ErrUserNotFound = AppError{
+		Code:    "USER_NOT_FOUND",
+		Message: "Utilisatuer introuvable",
+		Status:  http.StatusNotFound,
+	}
+
+	ErrRecordNotFound = AppError{
+		Code:    "RECORD_NOT_FOUND",
+		Message: "Enregistrement introuvable",
+		Status:  http.StatusNotFound,
+	}
+
+	ErrBadRequest = AppError{
+		Code:    "BAD_REQUEST",
+		Message: "Requête invalide",
+		Status:  http.StatusBadRequest,
+	}
+
+	ErrInternal = AppError{
+		Code:    "INTERNAL_ERROR",
+		Message: "Une erreur interne est survenue",
+		Status:  http.StatusInternalServerError,
+	}
+
+	ErrTokenMissing = AppError{
+		Code:    "TOKEN_MISSING",
+		Message: "Token manqant dans l'en-tête Authorization",
+		Status:  http.StatusUnauthorized,
+	}
+
+	ErrInvalidToken = AppError{
+		Code:    "INVALID_TOKEN",
+		Message: "Token invalide ou expiré",
+		Status:  http.StatusUnauthorized,
+	}
+
+	ErrAccessDenied = AppError{
+		Code:    "ACCESS_DENIED",
+		Message: "Accès refusé",
+		Status:  http.StatusForbidden,
+	}
+
+	ErrValidationFailed = AppError{
+		Code:    "VALIDATION_FAILED",
+		Message: "Echec de validation des données envoyées",
+		Status:  http.StatusUnprocessableEntity,
+	}
+
+	ErrBulkLimitExceeded = AppError{
+		Code:    "BULK_LIMIT_EXCEEDED",
+		Message: "Trop de tâches ciblées par l'opération groupée",
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+
+	ErrInvalidFilter = AppError{
+		Code:    "INVALID_FILTER",
+		Message: "Filtre ou tri invalide",
+		Status:  http.StatusBadRequest,
+	}
+
+	ErrInvalidPagination = AppError{
+		Code:    "INVALID_PAGINATION",
+		Message: "Paramètres de pagination invalides",
+		Status:  http.StatusBadRequest,
+	}
+
+	ErrInvalidSignature = AppError{
+		Code:    "INVALID_SIGNATURE",
+		Message: "Lien de téléchargement invalide",
+		Status:  http.StatusForbidden,
+	}
+
+	ErrLinkExpired = AppError{
+		Code:    "LINK_EXPIRED",
+		Message: "Lien de téléchargement expiré ou révoqué",
+		Status:  http.StatusGone,
+	}
+
+	ErrLinkPasswordRequired = AppError{
+		Code:    "LINK_PASSWORD_REQUIRED",
+		Message: "Mot de passe du lien manquant ou incorrect",
+		Status:  http.StatusUnauthorized,
+	}
+
+	ErrLinkLocked = AppError{
+		Code:    "LINK_LOCKED",
+		Message: "Trop de mots de passe incorrects pour ce lien, réessayez plus tard",
+		Status:  http.StatusTooManyRequests,
+	}
+
+	ErrEmptyFile = AppError{
+		Code:    "EMPTY_FILE",
+		Message: "Le fichier envoyé est vide",
+		Status:  http.StatusBadRequest,
+	}
+
+	ErrInvalidFileName = AppError{
+		Code:    "INVALID_FILE_NAME",
+		Message: "Nom de fichier invalide",
+		Status:  http.StatusBadRequest,
+	}
+
+	ErrFileTypeNotAllowed = AppError{
+		Code:    "FILE_TYPE_NOT_ALLOWED",
+		Message: "Type de fichier non autorisé",
+		Status:  http.StatusUnsupportedMediaType,
+	}
+
+	ErrFileTypeMismatch = AppError{
+		Code:    "FILE_TYPE_MISMATCH",
+		Message: "L'extension du fichier ne correspond pas à son contenu",
+		Status:  http.StatusUnsupportedMediaType,
+	}
+
+	ErrFileTooLarge = AppError{
+		Code:    "FILE_TOO_LARGE",
+		Message: "Fichier trop volumineux pour ce type",
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+
+	ErrTusVersion = AppError{
+		Code:    "TUS_VERSION_UNSUPPORTED",
+		Message: "Version du protocole tus non supportée (Tus-Resumable: 1.0.0 attendu)",
+		Status:  http.StatusPreconditionFailed,
+	}
+
+	ErrUploadOffsetMismatch = AppError{
+		Code:    "UPLOAD_OFFSET_MISMATCH",
+		Message: "Upload-Offset ne correspond pas aux octets déjà reçus",
+		Status:  http.StatusConflict,
+	}
+
+	ErrUploadLocked = AppError{
+		Code:    "UPLOAD_LOCKED",
+		Message: "Un autre envoi est en cours pour cet upload",
+		Status:  http.StatusLocked,
+	}
+
+	ErrUploadExpired = AppError{
+		Code:    "UPLOAD_EXPIRED",
+		Message: "Upload expiré",
+		Status:  http.StatusGone,
+	}
+
+	ErrInvalidContentType = AppError{
+		Code:    "INVALID_CONTENT_TYPE",
+		Message: "Content-Type non supporté",
+		Status:  http.StatusUnsupportedMediaType,
+	}
+
+	ErrJobRunning = AppError{
+		Code:    "JOB_ALREADY_RUNNING",
+		Message: "Ce traitement est déjà en cours",
+		Status:  http.StatusConflict,
+	}
+
+	ErrQuotaExceeded = AppError{
+		Code:    "QUOTA_EXCEEDED",
+		Message: "Quota de stockage dépassé",
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+
+	ErrFileQuarantined = AppError{
+		Code:    "FILE_QUARANTINED",
+		Message: "Fichier en attente d'analyse antivirus",
+		Status:  http.StatusLocked,
+	}
+
+	ErrFileInfected = AppError{
+		Code:    "FILE_INFECTED",
+		Message: "Fichier bloqué : une menace a été détectée",
+		Status:  http.StatusForbidden,
+	}
+
+	ErrArchiveTooLarge = AppError{
+		Code:    "ARCHIVE_TOO_LARGE",
+		Message: "Les fichiers demandés dépassent la taille maximale d'une archive",
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+
+	ErrPreviewUnavailable = AppError{
+		Code:    "PREVIEW_UNAVAILABLE",
+		Message: "Aucun aperçu disponible pour ce fichier",
+		Status:  http.StatusNotFound,
+	}
+
+	ErrNotAcceptable = AppError{
+		Code:    "NOT_ACCEPTABLE",
+		Message: "Format non supporté : csv, xlsx ou pdf",
+		Status:  http.StatusNotAcceptable,
+	}
+
+	ErrReportNotReady = AppError{
+		Code:    "REPORT_NOT_READY",
+		Message: "Le rapport n'est pas prêt ou sa génération a échoué",
+		Status:  http.StatusConflict,
+	}
+
+	ErrReportExpired = AppError{
+		Code:    "REPORT_EXPIRED",
+		Message: "Le rapport a expiré, il faut le redemander",
+		Status:  http.StatusGone,
+	}
+)
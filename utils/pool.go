@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Pool exécute des fonctions en parallèle, au plus limit à la fois (à la manière d'errgroup).
+// La première erreur annule le contexte passé aux autres fonctions et est renvoyée par Wait ;
+// les fonctions pas encore démarrées ne le sont plus. Un panic est rattrapé et traité comme une erreur.
+//
+//	pool, ctx := utils.NewPool(c.Request.Context(), 4)
+//	pool.Go(func(ctx context.Context) error {
+//		return database.DB.WithContext(ctx).Model(&models.Task{}).Count(&total).Error
+//	})
+//	err := pool.Wait()
+type Pool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	sem    chan struct{} //nil : pas de limite
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// NewPool crée un pool lié à ctx (en général c.Request.Context() : une déconnexion du client
+// arrête le travail). limit <= 0 : pas de limite. Le contexte renvoyé est celui des fonctions
+func NewPool(ctx context.Context, limit int) (*Pool, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Pool{ctx: ctx, cancel: cancel}
+	if limit > 0 {
+		p.sem = make(chan struct{}, limit)
+	}
+	return p, ctx
+}
+
+// Go attend une place libre puis lance fn dans une goroutine. Si le contexte est annulé
+// pendant l'attente (erreur précédente, client déconnecté), fn n'est pas lancée
+func (p *Pool) Go(fn func(ctx context.Context) error) {
+	if p.sem != nil {
+		select {
+		case p.sem <- struct{}{}:
+			//La place a pu se libérer en même temps que l'annulation
+			if err := p.ctx.Err(); err != nil {
+				<-p.sem
+				p.fail(err)
+				return
+			}
+		case <-p.ctx.Done():
+			p.fail(p.ctx.Err())
+			return
+		}
+	} else if err := p.ctx.Err(); err != nil {
+		p.fail(err)
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			if p.sem != nil {
+				<-p.sem
+			}
+			p.wg.Done()
+		}()
+		if err := p.run(fn); err != nil {
+			p.fail(err)
+		}
+	}()
+}
+
+// Un panic dans fn ne doit pas arrêter le serveur : il devient l'erreur de la fonction,
+// avec la pile d'appels pour le journal
+func (p *Pool) run(fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("utils: panic dans le pool: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn(p.ctx)
+}
+
+// Wait attend la fin des fonctions lancées et renvoie la première erreur
+func (p *Pool) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	return p.err
+}
+
+// Garde la première erreur et annule les autres fonctions
+func (p *Pool) fail(err error) {
+	p.once.Do(func() {
+		p.err = err
+		p.cancel()
+	})
+}
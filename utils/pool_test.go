@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		tasks int
+		want  int32 //nombre maximal de fonctions simultanées attendu
+	}{
+		{"une à la fois", 1, 10, 1},
+		{"limite 3", 3, 12, 3},
+		{"sans limite", 0, 8, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, _ := NewPool(context.Background(), tt.limit)
+			var running, peak, done atomic.Int32
+			release := make(chan struct{})
+			go func() {
+				//Laisse le temps à toutes les places de se remplir avant de libérer les fonctions
+				time.Sleep(50 * time.Millisecond)
+				close(release)
+			}()
+			for i := 0; i < tt.tasks; i++ {
+				pool.Go(func(ctx context.Context) error {
+					n := running.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					<-release
+					running.Add(-1)
+					done.Add(1)
+					return nil
+				})
+			}
+			if err := pool.Wait(); err != nil {
+				t.Fatal(err)
+			}
+			if got := peak.Load(); got != tt.want {
+				t.Errorf("%d fonctions simultanées au plus, attendu %d", got, tt.want)
+			}
+			if got := done.Load(); got != int32(tt.tasks) {
+				t.Errorf("%d fonctions terminées, attendu %d", got, tt.tasks)
+			}
+		})
+	}
+}
+
+func TestPoolFirstErrorCancels(t *testing.T) {
+	first := errors.New("première")
+	pool, ctx := NewPool(context.Background(), 2)
+
+	var started atomic.Int32
+	pool.Go(func(ctx context.Context) error {
+		started.Add(1)
+		return first
+	})
+	pool.Go(func(ctx context.Context) error {
+		started.Add(1)
+		<-ctx.Done() //annulée par l'erreur de la première fonction
+		return errors.New("seconde")
+	})
+	//Lancée après l'erreur : le contexte est déjà annulé, fn ne démarre pas
+	<-ctx.Done()
+	pool.Go(func(ctx context.Context) error {
+		started.Add(1)
+		return nil
+	})
+
+	if err := pool.Wait(); !errors.Is(err, first) {
+		t.Errorf("Wait = %v, attendu la première erreur", err)
+	}
+	if got := started.Load(); got != 2 {
+		t.Errorf("%d fonctions démarrées, attendu 2", got)
+	}
+}
+
+func TestPoolParentCancel(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	pool, _ := NewPool(parent, 1)
+
+	block := make(chan struct{})
+	pool.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		close(block)
+		return ctx.Err()
+	})
+	//La place est occupée : ce Go attend, puis abandonne quand le client se déconnecte
+	launched := make(chan struct{})
+	go func() {
+		pool.Go(func(ctx context.Context) error {
+			t.Error("fonction lancée après l'annulation")
+			return nil
+		})
+		close(launched)
+	}()
+	cancel()
+
+	select {
+	case <-launched:
+	case <-time.After(time.Second):
+		t.Fatal("Go bloqué après l'annulation du contexte")
+	}
+	<-block
+	if err := pool.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait = %v, attendu context.Canceled", err)
+	}
+}
+
+func TestPoolRecoversPanic(t *testing.T) {
+	pool, ctx := NewPool(context.Background(), 0)
+	pool.Go(func(ctx context.Context) error {
+		panic("boum")
+	})
+	pool.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	err := pool.Wait()
+	if err == nil || !strings.Contains(err.Error(), "boum") {
+		t.Fatalf("Wait = %v, attendu l'erreur du panic", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("le panic doit annuler le contexte du pool")
+	}
+}
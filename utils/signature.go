@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Clé HMAC des liens de téléchargement, partagée par toutes les instances du backend
+func downloadSigningKey() []byte {
+	if key := os.Getenv("DOWNLOAD_SIGNING_KEY"); key != "" {
+		return []byte(key)
+	}
+	return secretKey
+}
+
+// Signature d'un lien de téléchargement : HMAC-SHA256(lien, fichier, expiration)
+func SignDownload(linkID, fileID uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, downloadSigningKey())
+	mac.Write([]byte(linkID.String() + ":" + fileID.String() + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Vérification en temps constant de la signature
+func VerifyDownload(linkID, fileID uuid.UUID, expires int64, signature string) bool {
+	expected := SignDownload(linkID, fileID, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -1,41 +1,53 @@
-package utils
-
-import "net/http"
-
-type AppSuccessCRUD struct {
-	Code    string
-	Message string
-	Status  int
-}
-
-var (
-	SuccessRecordCreated = AppSuccessCRUD{
-		Code:    "RECORD_CREATED",
-		Message: "Enregistrement créé avec succès",
-		Status:  http.StatusCreated,
-	}
-
-	SuccessRecordUpdated = AppSuccessCRUD{
-		Code:    "RECORD_UPDATED",
-		Message: "Enregistrement mis à jour avec succès",
-		Status:  http.StatusOK,
-	}
-
-	SuccessRecordDelete = AppSuccessCRUD{
-		Code:    "RECORD_DELETED",
-		Message: "Enregistrement supprimé avec succès",
-		Status:  http.StatusOK,
-	}
-
-	SuccessRecordFetched = AppSuccessCRUD{
-		Code:    "RECORD_FETCHED",
-		Message: "Enregistrement récupéré avec succès",
-		Status:  http.StatusOK,
-	}
-
-	SuccessLogin = AppSuccessCRUD{
-		Code:    "LOGIN_SUCCESSFUL",
-		Message: "Connexion réussie",
-		Status:  http.StatusOK,
-	}
-)
+package utils
+
+import "net/http"
+
+type AppSuccessCRUD struct {
+	Code    string
+	Message string
+	Status  int
+}
+
+var (
+	SuccessRecordCreated = AppSuccessCRUD{
+		Code:    "RECORD_CREATED",
+		Message: "Enregistrement créé avec succès",
+		Status:  http.StatusCreated,
+	}
+
+	SuccessRecordUpdated = AppSuccessCRUD{
+		Code:    "RECORD_UPDATED",
+		Message: "Enregistrement mis à jour avec succès",
+		Status:  http.StatusOK,
+	}
+
+	SuccessRecordDelete = AppSuccessCRUD{
+		Code:    "RECORD_DELETED",
+		Message: "Enregistrement supprimé avec succès",
+		Status:  http.StatusOK,
+	}
+
+	SuccessRecordFetched = AppSuccessCRUD{
+		Code:    "RECORD_FETCHED",
+		Message: "Enregistrement récupéré avec succès",
+		Status:  http.StatusOK,
+	}
+
+	SuccessLogin = AppSuccessCRUD{
+		Code:    "LOGIN_SUCCESSFUL",
+		Message: "Connexion réussie",
+		Status:  http.StatusOK,
+	}
+
+	SuccessJobStarted = AppSuccessCRUD{
+		Code:    "JOB_STARTED",
+		Message: "Traitement lancé en arrière-plan",
+		Status:  http.StatusAccepted,
+	}
+
+	SuccessPreviewPending = AppSuccessCRUD{
+		Code:    "PREVIEW_PENDING",
+		Message: "Aperçu en cours de génération",
+		Status:  http.StatusAccepted,
+	}
+)